@@ -4,16 +4,26 @@ import (
 	"context"
 	"fmt"
 	"letterboxd-tracker/database"
+	"letterboxd-tracker/importer"
+	"letterboxd-tracker/report"
 	"letterboxd-tracker/scraper"
 	"log"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // App struct
 type App struct {
 	ctx context.Context
 	db  *database.MovieDB
+
+	// cancelScrape stops the running scrape, nil when idle
+	scrapeMu     sync.Mutex
+	cancelScrape context.CancelFunc
 }
 
 // NewApp creates a new App application struct
@@ -52,68 +62,288 @@ func (a *App) shutdown(ctx context.Context) {
 	}
 }
 
-// GetAllMovies returns all movies from the database
-func (a *App) GetAllMovies() ([]database.Movie, error) {
+// QueryMovies returns the movies matching a filter/sort/page query and
+// how many movies match in total
+func (a *App) QueryMovies(query database.MovieQuery) (database.MovieQueryResult, error) {
+	if a.db == nil {
+		return database.MovieQueryResult{}, fmt.Errorf("database not initialized")
+	}
+	return a.db.QueryMovies(query)
+}
+
+// QueryWatchlist returns the watchlist films matching a filter/sort/page
+// query, in watchlist order by default, and how many match in total
+func (a *App) QueryWatchlist(query database.WatchlistQuery) (database.WatchlistQueryResult, error) {
+	if a.db == nil {
+		return database.WatchlistQueryResult{}, fmt.Errorf("database not initialized")
+	}
+	return a.db.QueryWatchlist(query)
+}
+
+// GetLists returns the user's Letterboxd lists, by name
+func (a *App) GetLists() ([]database.List, error) {
 	if a.db == nil {
 		return nil, fmt.Errorf("database not initialized")
 	}
-	return a.db.GetAllMovies()
+	return a.db.GetLists()
 }
 
-// GetStats returns statistics about the movie collection
-func (a *App) GetStats() (map[string]interface{}, error) {
+// GetListEntries returns the films of a list in list order
+func (a *App) GetListEntries(slug string) ([]database.ListEntry, error) {
 	if a.db == nil {
 		return nil, fmt.Errorf("database not initialized")
 	}
-	return a.db.GetStats()
+	return a.db.GetListEntries(slug)
+}
+
+// DiffList returns the films added to, removed from and moved within a
+// list by the last sync that changed it
+func (a *App) DiffList(slug string) (database.ListDiff, error) {
+	if a.db == nil {
+		return database.ListDiff{}, fmt.Errorf("database not initialized")
+	}
+	return a.db.DiffList(slug)
+}
+
+// GetStats returns statistics about the movie collection,
+// or about the subset matching filter (a release year, a rating range)
+func (a *App) GetStats(filter database.StatsFilter) (database.Stats, error) {
+	if a.db == nil {
+		return database.Stats{}, fmt.Errorf("database not initialized")
+	}
+	return a.db.GetStats(filter)
 }
 
-// ScrapeUserData scrapes data for a Letterboxd user and stores it in the database
+// GetDisagreementStats compares personal ratings with the Letterboxd
+// average, optionally limited by filter (a release year, a genre)
+func (a *App) GetDisagreementStats(filter database.StatsFilter) (database.DisagreementStats, error) {
+	if a.db == nil {
+		return database.DisagreementStats{}, fmt.Errorf("database not initialized")
+	}
+	return a.db.GetDisagreementStats(filter)
+}
+
+// GetTagCounts returns how many diary entries and films carry each tag,
+// optionally limited by filter
+func (a *App) GetTagCounts(filter database.StatsFilter) ([]database.TagStat, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	return a.db.GetTagCounts(filter)
+}
+
+// GetTagCooccurrence returns the pairs of tags most often used together,
+// only those including tag when it is set
+func (a *App) GetTagCooccurrence(tag string, limit int) ([]database.TagPair, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	return a.db.GetTagCooccurrence(tag, limit)
+}
+
+// ScrapeUserData scrapes data for a Letterboxd user and stores it in the database.
+// Progress is emitted to the frontend as "scrape:progress" events
 func (a *App) ScrapeUserData(username string) error {
+	_, err := a.runSync(username, scraper.SyncOptions{})
+	return err
+}
+
+// SyncUserData runs an incremental sync that only walks the most recently
+// watched films, stopping once it reaches films already in the database
+func (a *App) SyncUserData(username string) error {
+	_, err := a.runSync(username, scraper.SyncOptions{Incremental: true})
+	return err
+}
+
+// RefreshUserData re-reads the user's rating for every film and re-scrapes
+// details older than maxAgeDays (0 uses the default), returning the changed fields
+func (a *App) RefreshUserData(username string, maxAgeDays int) (scraper.Result, error) {
+	return a.runSync(username, scraper.SyncOptions{
+		Refresh: true,
+		MaxAge:  time.Duration(maxAgeDays) * 24 * time.Hour,
+	})
+}
+
+// GetSyncState returns when a user was last synced
+func (a *App) GetSyncState(username string) (database.SyncState, error) {
 	if a.db == nil {
-		return fmt.Errorf("database not initialized")
+		return database.SyncState{}, fmt.Errorf("database not initialized")
 	}
+	state, _, err := a.db.GetSyncState(username)
+	return state, err
+}
 
-	s := scraper.NewScraper(a.db)
-	return s.ScrapeUser(username)
+// runSync runs one scrape at a time, cancellable through CancelScrape
+func (a *App) runSync(username string, opts scraper.SyncOptions) (scraper.Result, error) {
+	if a.db == nil {
+		return scraper.Result{}, fmt.Errorf("database not initialized")
+	}
+
+	a.scrapeMu.Lock()
+	if a.cancelScrape != nil {
+		a.scrapeMu.Unlock()
+		return scraper.Result{}, fmt.Errorf("a scrape is already running")
+	}
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.cancelScrape = cancel
+	a.scrapeMu.Unlock()
+
+	defer func() {
+		a.scrapeMu.Lock()
+		a.cancelScrape = nil
+		a.scrapeMu.Unlock()
+		cancel()
+	}()
+
+	s := scraper.NewScraper(a.db, scraper.NewHTTPFetcher())
+	s.SetProgressHandler(func(p scraper.Progress) {
+		runtime.EventsEmit(a.ctx, "scrape:progress", p)
+	})
+	return s.Sync(ctx, username, opts)
 }
 
-// SearchMovies searches for movies by title
-func (a *App) SearchMovies(query string) ([]database.Movie, error) {
+// CancelScrape stops the running scrape, if any
+func (a *App) CancelScrape() {
+	a.scrapeMu.Lock()
+	defer a.scrapeMu.Unlock()
+
+	if a.cancelScrape != nil {
+		a.cancelScrape()
+	}
+}
+
+// ImportLetterboxdExport loads the official Letterboxd data export ZIP at path
+func (a *App) ImportLetterboxdExport(path string) (importer.Summary, error) {
+	if a.db == nil {
+		return importer.Summary{}, fmt.Errorf("database not initialized")
+	}
+	return importer.NewImporter(a.db).ImportZip(path)
+}
+
+// SelectExportFile opens a native file dialog to pick a Letterboxd export ZIP.
+// Returns an empty path if the dialog was cancelled
+func (a *App) SelectExportFile() (string, error) {
+	return runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Select Letterboxd export",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Letterboxd export (*.zip)", Pattern: "*.zip"},
+		},
+	})
+}
+
+// SearchFullText searches titles, people, synopses and reviews, best matches first
+func (a *App) SearchFullText(query string, limit int) ([]database.SearchResult, error) {
 	if a.db == nil {
 		return nil, fmt.Errorf("database not initialized")
 	}
-	return a.db.SearchByTitle(query)
+	return a.db.Search(query, limit)
 }
 
-// GetMoviesByRating returns movies with a rating >= minRating
-func (a *App) GetMoviesByRating(minRating float64) ([]database.Movie, error) {
+// GetViewings returns every diary viewing of a movie, most recent first
+func (a *App) GetViewings(letterboxdID string) ([]database.Viewing, error) {
 	if a.db == nil {
 		return nil, fmt.Errorf("database not initialized")
 	}
-	return a.db.GetMoviesByRating(minRating)
+	return a.db.GetViewings(letterboxdID)
 }
 
-// GetMoviesByYear returns all movies from a specific year
-func (a *App) GetMoviesByYear(year int) ([]database.Movie, error) {
+// GetReviews returns every review of a movie, most recent first
+func (a *App) GetReviews(letterboxdID string) ([]database.Review, error) {
 	if a.db == nil {
 		return nil, fmt.Errorf("database not initialized")
 	}
-	return a.db.GetMoviesByYear(year)
+	return a.db.GetReviews(letterboxdID)
+}
+
+// GetRatingHistory returns every recorded rating change of a film
+func (a *App) GetRatingHistory(letterboxdID string) ([]database.RatingChange, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	return a.db.GetRatingHistory(letterboxdID)
+}
+
+// GetReratedFilms returns the films whose personal rating changed between
+// two dates (YYYY-MM-DD, inclusive). An empty date leaves that end open
+func (a *App) GetReratedFilms(fromDate, toDate string) ([]database.RatingChange, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	var from, to time.Time
+	var err error
+	if fromDate != "" {
+		if from, err = time.ParseInLocation("2006-01-02", fromDate, time.Local); err != nil {
+			return nil, fmt.Errorf("invalid from date %q: %w", fromDate, err)
+		}
+	}
+	if toDate != "" {
+		if to, err = time.ParseInLocation("2006-01-02", toDate, time.Local); err != nil {
+			return nil, fmt.Errorf("invalid to date %q: %w", toDate, err)
+		}
+		to = to.AddDate(0, 0, 1).Add(-time.Second)
+	}
+
+	return a.db.GetReratedFilms(from, to)
+}
+
+// GetYearInReview summarises the films logged in the diary during year
+func (a *App) GetYearInReview(year int) (database.YearInReview, error) {
+	if a.db == nil {
+		return database.YearInReview{}, fmt.Errorf("database not initialized")
+	}
+	return a.db.GetYearInReview(year)
 }
 
-// DeleteDatabase deletes all movies from the database
+// ExportYearInReview asks where to save and writes the year in review as a
+// self-contained HTML file. Returns the saved path, empty if cancelled
+func (a *App) ExportYearInReview(year int) (string, error) {
+	if a.db == nil {
+		return "", fmt.Errorf("database not initialized")
+	}
+
+	review, err := a.db.GetYearInReview(year)
+	if err != nil {
+		return "", err
+	}
+
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export year in review",
+		DefaultFilename: fmt.Sprintf("letterboxd-%d-in-review.html", year),
+		Filters: []runtime.FileFilter{
+			{DisplayName: "HTML (*.html)", Pattern: "*.html"},
+		},
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if err := report.WriteYearInReview(file, review); err != nil {
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return path, nil
+}
+
+// DeleteDatabase deletes all stored data, including the sync state
 func (a *App) DeleteDatabase() error {
 	if a.db == nil {
 		return fmt.Errorf("database not initialized")
 	}
-	
-	// Delete all movies
-	_, err := a.db.GetDB().Exec("DELETE FROM movies")
-	if err != nil {
-		return fmt.Errorf("failed to delete movies: %w", err)
+
+	if err := a.db.Clear(); err != nil {
+		return fmt.Errorf("failed to delete database: %w", err)
 	}
 
 	log.Println("Database cleared successfully")
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// saveCredits replaces the credits of a movie inside the given transaction,
+// creating or renaming people as needed
+func saveCredits(tx *sql.Tx, movieID string, credits []Credit) error {
+	if _, err := tx.Exec("DELETE FROM credits WHERE movie_id = ?", movieID); err != nil {
+		return fmt.Errorf("failed to clear credits: %w", err)
+	}
+
+	personStmt, err := tx.Prepare(`
+	INSERT INTO people (slug, name) VALUES (?, ?)
+	ON CONFLICT(slug) DO UPDATE SET name = excluded.name
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare person statement: %w", err)
+	}
+	defer personStmt.Close()
+
+	creditStmt, err := tx.Prepare(`
+	INSERT OR IGNORE INTO credits (movie_id, person_id, role, billing_order, character_name)
+	VALUES (?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare credit statement: %w", err)
+	}
+	defer creditStmt.Close()
+
+	for _, credit := range credits {
+		if credit.PersonSlug == "" {
+			continue
+		}
+		if _, err := personStmt.Exec(credit.PersonSlug, credit.Name); err != nil {
+			return fmt.Errorf("failed to save person %s: %w", credit.PersonSlug, err)
+		}
+
+		var character sql.NullString
+		if credit.CharacterName != "" {
+			character = sql.NullString{String: credit.CharacterName, Valid: true}
+		}
+		_, err := creditStmt.Exec(movieID, credit.PersonSlug, credit.Role, credit.BillingOrder, character)
+		if err != nil {
+			return fmt.Errorf("failed to save credit for %s: %w", credit.PersonSlug, err)
+		}
+	}
+
+	return nil
+}
+
+// GetCredits returns the credits of a movie ordered by role and billing
+func (m *MovieDB) GetCredits(movieID string) ([]Credit, error) {
+	query := `
+	SELECT p.slug, p.name, c.role, c.billing_order, c.character_name
+	FROM credits c
+	JOIN people p ON p.slug = c.person_id
+	WHERE c.movie_id = ?
+	ORDER BY c.role ASC, c.billing_order ASC
+	`
+
+	rows, err := m.db.Query(query, movieID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query credits: %w", err)
+	}
+	defer rows.Close()
+
+	var credits []Credit
+	for rows.Next() {
+		var credit Credit
+		var character sql.NullString
+		if err := rows.Scan(&credit.PersonSlug, &credit.Name, &credit.Role, &credit.BillingOrder, &character); err != nil {
+			return nil, fmt.Errorf("failed to scan credit row: %w", err)
+		}
+		credit.CharacterName = character.String
+		credits = append(credits, credit)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return credits, nil
+}
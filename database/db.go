@@ -22,8 +22,9 @@ func NewMovieDB(dbPath string) (*MovieDB, error) {
 		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
 
-	// Open SQLite database
-	db, err := sql.Open("sqlite3", dbPath)
+	// Open SQLite database with foreign keys enforced (credits cascade with movies)
+	// and a busy timeout so concurrent scraper workers wait for the write lock
+	db, err := sql.Open("sqlite3", dbPath+"?_foreign_keys=on&_busy_timeout=5000")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -35,46 +36,15 @@ func NewMovieDB(dbPath string) (*MovieDB, error) {
 
 	movieDB := &MovieDB{db: db}
 
-	// Create tables
-	if err := movieDB.createTables(); err != nil {
+	// Apply any pending schema migrations
+	if err := movieDB.migrate(); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to create tables: %w", err)
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
 	return movieDB, nil
 }
 
-// createTables creates the necessary database tables and indexes
-func (m *MovieDB) createTables() error {
-	schema := `
-	CREATE TABLE IF NOT EXISTS movies (
-		letterboxd_id TEXT PRIMARY KEY,
-		title TEXT NOT NULL,
-		year INTEGER,
-		letterboxd_url TEXT NOT NULL,
-		rating REAL,
-		letterboxd_rating REAL,
-		length INTEGER,
-		date_added TEXT NOT NULL,
-		poster_url TEXT,
-		director TEXT,
-		cast TEXT,
-		writers TEXT
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_title ON movies(title);
-	CREATE INDEX IF NOT EXISTS idx_rating ON movies(rating);
-	CREATE INDEX IF NOT EXISTS idx_year ON movies(year);
-	`
-
-	_, err := m.db.Exec(schema)
-	if err != nil {
-		return fmt.Errorf("failed to execute schema: %w", err)
-	}
-
-	return nil
-}
-
 // Close closes the database connection
 func (m *MovieDB) Close() error {
 	return m.db.Close()
@@ -93,6 +63,37 @@ func (m *MovieDB) MovieExists(letterboxdID string) (bool, error) {
 	return count > 0, nil
 }
 
+// clearedTables lists every table Clear empties. Rows tied to a film
+// cascade with it, but lookups such as people and the sync state would
+// otherwise outlive the films that created them
+var clearedTables = []string{
+	"movies", "credits", "rating_history", "people",
+	"genres", "countries", "languages", "studios", "themes",
+	"watchlist", "lists", "tags", "movie_search", "sync_state",
+}
+
+// Clear deletes all stored data in one transaction, keeping the schema.
+// The next sync after a Clear is a full one
+func (m *MovieDB) Clear() error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range clearedTables {
+		if _, err := tx.Exec("DELETE FROM " + table); err != nil {
+			return fmt.Errorf("failed to clear %s: %w", table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
 // GetDB returns the underlying sql.DB connection for testing purposes
 func (m *MovieDB) GetDB() *sql.DB {
 	return m.db
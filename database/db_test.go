@@ -0,0 +1,57 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClear(t *testing.T) {
+	db := newTestDB(t)
+
+	movie := Movie{
+		LetterboxdID:  "/film/paterson",
+		Title:         "Paterson",
+		LetterboxdURL: "/film/paterson/",
+		Rating:        4.5,
+		Director:      "Jim Jarmusch",
+		Credits:       []Credit{{PersonSlug: "jim-jarmusch", Name: "Jim Jarmusch", Role: RoleDirector}},
+		Genres:        []Term{{Slug: "drama", Name: "Drama"}},
+	}
+	if err := db.AddMovie(movie); err != nil {
+		t.Fatalf("AddMovie: %v", err)
+	}
+	watched := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	err := db.AddViewing(Viewing{
+		LetterboxdViewingID: ViewingKey(movie.LetterboxdID, watched),
+		LetterboxdID:        movie.LetterboxdID,
+		WatchedDate:         watched,
+		Tags:                []Term{{Slug: "theatre", Name: "theatre"}},
+	})
+	if err != nil {
+		t.Fatalf("AddViewing: %v", err)
+	}
+	if err := db.SaveWatchlist([]WatchlistEntry{{Movie: Movie{LetterboxdID: "/film/amelie", Title: "Amélie"}, Position: 1}}); err != nil {
+		t.Fatalf("SaveWatchlist: %v", err)
+	}
+	if err := db.RecordSync("bob", time.Now(), true); err != nil {
+		t.Fatalf("RecordSync: %v", err)
+	}
+
+	if err := db.Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+
+	for _, table := range append(clearedTables, "viewings", "viewing_tags", "movie_genres") {
+		var count int
+		if err := db.db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count); err != nil {
+			t.Fatalf("count %s: %v", table, err)
+		}
+		if count != 0 {
+			t.Errorf("%s has %d rows after Clear", table, count)
+		}
+	}
+
+	if _, found, err := db.GetSyncState("bob"); err != nil || found {
+		t.Errorf("GetSyncState = found %v, err %v, want no state", found, err)
+	}
+}
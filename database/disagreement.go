@@ -0,0 +1,221 @@
+package database
+
+import (
+	"fmt"
+	"math"
+)
+
+// minDirectorFilms is how many rated films a director needs before their
+// bias is reported, so one outlier does not dominate the list
+const minDirectorFilms = 3
+
+// Disagreement is a film rated away from the Letterboxd average.
+// Difference is the personal rating minus the Letterboxd rating
+type Disagreement struct {
+	Movie      Movie   `json:"movie"`
+	Difference float64 `json:"difference"`
+}
+
+// GroupBias is the mean signed deviation from the Letterboxd average over
+// the films of one director, decade or genre. Key is the person or genre
+// slug, or the decade ("1970")
+type GroupBias struct {
+	Key           string  `json:"key"`
+	Name          string  `json:"name"`
+	Films         int     `json:"films"`
+	MeanDeviation float64 `json:"mean_deviation"`
+}
+
+// DisagreementStats compares personal ratings with the Letterboxd average
+// over every film that has both. Deviations are personal minus Letterboxd,
+// so a negative MeanDeviation means a harsher rater than the crowd.
+// Correlation is Pearson's r, 0 when it is undefined
+type DisagreementStats struct {
+	RatedFilms            int            `json:"rated_films"`
+	MeanDeviation         float64        `json:"mean_deviation"`
+	MeanAbsoluteDeviation float64        `json:"mean_absolute_deviation"`
+	Correlation           float64        `json:"correlation"`
+	AboveCrowd            []Disagreement `json:"above_crowd"`
+	BelowCrowd            []Disagreement `json:"below_crowd"`
+	ByDirector            []GroupBias    `json:"by_director"`
+	ByDecade              []GroupBias    `json:"by_decade"`
+	ByGenre               []GroupBias    `json:"by_genre"`
+}
+
+// GetDisagreementStats compares personal and Letterboxd ratings over the
+// movies matching filter. AboveCrowd and BelowCrowd hold up to ten films each;
+// directors need minDirectorFilms rated films to be listed
+func (m *MovieDB) GetDisagreementStats(filter StatsFilter) (DisagreementStats, error) {
+	stats := DisagreementStats{}
+
+	where, args, err := filter.movieQuery().where()
+	if err != nil {
+		return stats, err
+	}
+	// Subquery selecting the ids of the movies in scope that have both ratings
+	inScope := "SELECT letterboxd_id FROM movies " + where
+	if where == "" {
+		inScope += "WHERE "
+	} else {
+		inScope += " AND "
+	}
+	inScope += "rating > 0 AND letterboxd_rating > 0"
+
+	// Sums for the mean deviations and Pearson's r
+	var n int
+	var sumX, sumY, sumXY, sumXX, sumYY, sumAbs float64
+	err = m.db.QueryRow(`
+		SELECT COUNT(*),
+			COALESCE(SUM(rating), 0), COALESCE(SUM(letterboxd_rating), 0),
+			COALESCE(SUM(rating * letterboxd_rating), 0),
+			COALESCE(SUM(rating * rating), 0), COALESCE(SUM(letterboxd_rating * letterboxd_rating), 0),
+			COALESCE(SUM(ABS(rating - letterboxd_rating)), 0)
+		FROM movies
+		WHERE letterboxd_id IN (`+inScope+`)
+	`, args...).Scan(&n, &sumX, &sumY, &sumXY, &sumXX, &sumYY, &sumAbs)
+	if err != nil {
+		return stats, fmt.Errorf("failed to get rating sums: %w", err)
+	}
+
+	stats.RatedFilms = n
+	if n > 0 {
+		stats.MeanDeviation = round2((sumX - sumY) / float64(n))
+		stats.MeanAbsoluteDeviation = round2(sumAbs / float64(n))
+	}
+	stats.Correlation = pearson(n, sumX, sumY, sumXY, sumXX, sumYY)
+
+	// Sorted by signed difference, so agreeing films only trail the list
+	above, err := m.getDisagreements(inScope, args, "rating - letterboxd_rating DESC", 10)
+	if err != nil {
+		return stats, err
+	}
+	stats.AboveCrowd = filterDisagreements(above, func(d float64) bool { return d > 0 })
+	below, err := m.getDisagreements(inScope, args, "rating - letterboxd_rating ASC", 10)
+	if err != nil {
+		return stats, err
+	}
+	stats.BelowCrowd = filterDisagreements(below, func(d float64) bool { return d < 0 })
+
+	stats.ByDirector, err = m.groupBias(`
+		SELECT p.slug, p.name, COUNT(*), AVG(m.rating - m.letterboxd_rating) AS bias
+		FROM credits c
+		JOIN people p ON p.slug = c.person_id
+		JOIN movies m ON m.letterboxd_id = c.movie_id
+		WHERE c.role = 'director' AND c.movie_id IN (`+inScope+`)
+		GROUP BY p.slug
+		HAVING COUNT(*) >= ?
+		ORDER BY bias DESC, p.name ASC
+	`, append(append([]interface{}{}, args...), minDirectorFilms)...)
+	if err != nil {
+		return stats, err
+	}
+
+	stats.ByDecade, err = m.groupBias(`
+		SELECT CAST((year / 10) * 10 AS TEXT) AS decade, ((year / 10) * 10) || 's', COUNT(*), AVG(rating - letterboxd_rating)
+		FROM movies
+		WHERE year > 0 AND letterboxd_id IN (`+inScope+`)
+		GROUP BY decade
+		ORDER BY decade ASC
+	`, args...)
+	if err != nil {
+		return stats, err
+	}
+
+	stats.ByGenre, err = m.groupBias(`
+		SELECT t.slug, t.name, COUNT(*), AVG(m.rating - m.letterboxd_rating) AS bias
+		FROM movie_genres g
+		JOIN genres t ON t.slug = g.genre_id
+		JOIN movies m ON m.letterboxd_id = g.movie_id
+		WHERE g.movie_id IN (`+inScope+`)
+		GROUP BY t.slug
+		ORDER BY bias DESC, t.name ASC
+	`, args...)
+	if err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+// groupBias runs a query selecting (key, name, films, mean deviation) rows
+func (m *MovieDB) groupBias(query string, args ...interface{}) ([]GroupBias, error) {
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rating bias: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []GroupBias{}
+	for rows.Next() {
+		var group GroupBias
+		if err := rows.Scan(&group.Key, &group.Name, &group.Films, &group.MeanDeviation); err != nil {
+			return nil, fmt.Errorf("failed to scan rating bias row: %w", err)
+		}
+		group.MeanDeviation = round2(group.MeanDeviation)
+		groups = append(groups, group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return groups, nil
+}
+
+// getDisagreements returns the movies in scope that have both ratings,
+// ordered by the given expression over rating and letterboxd_rating
+func (m *MovieDB) getDisagreements(inScope string, args []interface{}, orderBy string, limit int) ([]Disagreement, error) {
+	params := append(append([]interface{}{}, args...), limit)
+	movies, err := m.selectMovies(`
+		SELECT `+movieColumns+`
+		FROM movies
+		WHERE rating > 0 AND letterboxd_rating > 0 AND letterboxd_id IN (`+inScope+`)
+		ORDER BY `+orderBy+`, title ASC
+		LIMIT ?
+	`, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	disagreements := make([]Disagreement, 0, len(movies))
+	for _, movie := range movies {
+		disagreements = append(disagreements, Disagreement{
+			Movie:      movie,
+			Difference: round2(movie.Rating - movie.LetterboxdRating),
+		})
+	}
+
+	return disagreements, nil
+}
+
+// filterDisagreements keeps the disagreements whose difference satisfies keep
+func filterDisagreements(disagreements []Disagreement, keep func(float64) bool) []Disagreement {
+	kept := []Disagreement{}
+	for _, d := range disagreements {
+		if keep(d.Difference) {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+// pearson computes the correlation coefficient from running sums,
+// returning 0 for fewer than two films or when either rating never varies
+func pearson(n int, sumX, sumY, sumXY, sumXX, sumYY float64) float64 {
+	if n < 2 {
+		return 0
+	}
+	fn := float64(n)
+	cov := fn*sumXY - sumX*sumY
+	varX := fn*sumXX - sumX*sumX
+	varY := fn*sumYY - sumY*sumY
+	if varX <= 0 || varY <= 0 {
+		return 0
+	}
+	return math.Round(cov/math.Sqrt(varX*varY)*1000) / 1000
+}
+
+// round2 rounds to two decimals, enough for ratings out of five
+func round2(x float64) float64 {
+	return math.Round(x*100) / 100
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestGetDisagreementStatsEmpty(t *testing.T) {
+	db := newTestDB(t)
+	// Neither film has both ratings
+	addMovies(t, db,
+		Movie{LetterboxdID: "/film/ghost", Title: "Ghost", LetterboxdRating: 3.4},
+		Movie{LetterboxdID: "/film/heat", Title: "Heat", Rating: 4},
+	)
+
+	stats, err := db.GetDisagreementStats(StatsFilter{})
+	if err != nil {
+		t.Fatalf("GetDisagreementStats: %v", err)
+	}
+	if stats.RatedFilms != 0 || stats.MeanDeviation != 0 || stats.Correlation != 0 {
+		t.Errorf("stats = %+v, want zeros", stats)
+	}
+	if stats.AboveCrowd == nil || stats.BelowCrowd == nil || stats.ByDirector == nil || stats.ByDecade == nil || stats.ByGenre == nil {
+		t.Errorf("nil list in %+v", stats)
+	}
+}
+
+func TestGetDisagreementStats(t *testing.T) {
+	db := newTestDB(t)
+
+	director := func(slug, name string) []Credit {
+		return []Credit{{PersonSlug: slug, Name: name, Role: RoleDirector}}
+	}
+	drama := []Term{{Slug: "drama", Name: "Drama"}}
+	comedy := []Term{{Slug: "comedy", Name: "Comedy"}}
+	addMovies(t, db,
+		Movie{LetterboxdID: "/film/barry-lyndon", Title: "Barry Lyndon", Year: 1975, Rating: 4, LetterboxdRating: 3,
+			Credits: director("terrence-malick", "Terrence Malick"), Genres: drama},
+		Movie{LetterboxdID: "/film/days-of-heaven", Title: "Days of Heaven", Year: 1978, Rating: 3, LetterboxdRating: 3.5,
+			Credits: director("terrence-malick", "Terrence Malick"), Genres: drama},
+		Movie{LetterboxdID: "/film/the-tree-of-life", Title: "The Tree of Life", Year: 2011, Rating: 5, LetterboxdRating: 4,
+			Credits: director("terrence-malick", "Terrence Malick"), Genres: comedy},
+		Movie{LetterboxdID: "/film/amelie", Title: "Amélie", Year: 2001, Rating: 2, LetterboxdRating: 2,
+			Credits: director("jean-pierre-jeunet", "Jean-Pierre Jeunet"), Genres: drama},
+		// Left out: missing one of the two ratings
+		Movie{LetterboxdID: "/film/ghost", Title: "Ghost", Year: 1990, LetterboxdRating: 4.2, Genres: drama},
+		Movie{LetterboxdID: "/film/heat", Title: "Heat", Year: 1995, Rating: 1, Genres: drama},
+	)
+
+	stats, err := db.GetDisagreementStats(StatsFilter{})
+	if err != nil {
+		t.Fatalf("GetDisagreementStats: %v", err)
+	}
+
+	// Deviations +1, -0.5, +1 and 0
+	if stats.RatedFilms != 4 || stats.MeanDeviation != 0.38 || stats.MeanAbsoluteDeviation != 0.63 {
+		t.Errorf("rated %d, mean %v, absolute %v, want 4, 0.38, 0.63",
+			stats.RatedFilms, stats.MeanDeviation, stats.MeanAbsoluteDeviation)
+	}
+	if stats.Correlation != 0.832 {
+		t.Errorf("correlation = %v, want 0.832", stats.Correlation)
+	}
+
+	titles := func(disagreements []Disagreement) []string {
+		var titles []string
+		for _, d := range disagreements {
+			titles = append(titles, d.Movie.Title)
+		}
+		return titles
+	}
+	// Ties ordered by title; the film rated like the crowd is in neither list
+	if got := titles(stats.AboveCrowd); len(got) != 2 || got[0] != "Barry Lyndon" || got[1] != "The Tree of Life" {
+		t.Errorf("above crowd = %v, want [Barry Lyndon The Tree of Life]", got)
+	}
+	if len(stats.BelowCrowd) != 1 || stats.BelowCrowd[0].Movie.Title != "Days of Heaven" || stats.BelowCrowd[0].Difference != -0.5 {
+		t.Errorf("below crowd = %+v, want Days of Heaven at -0.5", stats.BelowCrowd)
+	}
+
+	tests := []struct {
+		name string
+		got  []GroupBias
+		want []GroupBias
+	}{
+		{"by director", stats.ByDirector, []GroupBias{
+			{Key: "terrence-malick", Name: "Terrence Malick", Films: 3, MeanDeviation: 0.5},
+		}},
+		{"by decade", stats.ByDecade, []GroupBias{
+			{Key: "1970", Name: "1970s", Films: 2, MeanDeviation: 0.25},
+			{Key: "2000", Name: "2000s", Films: 1, MeanDeviation: 0},
+			{Key: "2010", Name: "2010s", Films: 1, MeanDeviation: 1},
+		}},
+		{"by genre", stats.ByGenre, []GroupBias{
+			{Key: "comedy", Name: "Comedy", Films: 1, MeanDeviation: 1},
+			{Key: "drama", Name: "Drama", Films: 3, MeanDeviation: 0.17},
+		}},
+	}
+	for _, tt := range tests {
+		if len(tt.got) != len(tt.want) {
+			t.Errorf("%s = %+v, want %+v", tt.name, tt.got, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if tt.got[i] != tt.want[i] {
+				t.Errorf("%s[%d] = %+v, want %+v", tt.name, i, tt.got[i], tt.want[i])
+			}
+		}
+	}
+
+	// A filter narrows the comparison to its scope
+	stats, err = db.GetDisagreementStats(StatsFilter{Decade: 1970})
+	if err != nil {
+		t.Fatalf("GetDisagreementStats: %v", err)
+	}
+	if stats.RatedFilms != 2 || stats.MeanDeviation != 0.25 || len(stats.ByDirector) != 0 {
+		t.Errorf("1970s = %d films, mean %v, %d directors, want 2, 0.25, 0", stats.RatedFilms, stats.MeanDeviation, len(stats.ByDirector))
+	}
+}
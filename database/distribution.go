@@ -0,0 +1,120 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// RatingBucket is the number of movies rated Rating, or for Letterboxd
+// averages, rated from Rating up to the next bucket
+type RatingBucket struct {
+	Rating float64 `json:"rating"`
+	Count  int     `json:"count"`
+}
+
+// RatingDistribution is a histogram of ratings with summary statistics over
+// the rated movies. Buckets covers the whole scale, empty buckets included.
+// Mode is the rating of the fullest bucket, the lowest one on ties
+type RatingDistribution struct {
+	Buckets []RatingBucket `json:"buckets"`
+	Rated   int            `json:"rated"`
+	Unrated int            `json:"unrated"`
+	Median  float64        `json:"median"`
+	Mode    float64        `json:"mode"`
+	StdDev  float64        `json:"std_dev"`
+}
+
+// getRatingDistributions builds the personal (half-star) and Letterboxd
+// average (0.1 wide) rating distributions of the movies matching where
+func (m *MovieDB) getRatingDistributions(where string, args []interface{}) (RatingDistribution, RatingDistribution, error) {
+	rows, err := m.db.Query(`
+		SELECT COALESCE(rating, 0), COALESCE(letterboxd_rating, 0)
+		FROM movies `+where, args...)
+	if err != nil {
+		return RatingDistribution{}, RatingDistribution{}, fmt.Errorf("failed to query ratings: %w", err)
+	}
+	defer rows.Close()
+
+	var personal, letterboxd []float64
+	var total int
+	for rows.Next() {
+		var rating, letterboxdRating float64
+		if err := rows.Scan(&rating, &letterboxdRating); err != nil {
+			return RatingDistribution{}, RatingDistribution{}, fmt.Errorf("failed to scan rating row: %w", err)
+		}
+		total++
+		if rating > 0 {
+			personal = append(personal, rating)
+		}
+		if letterboxdRating > 0 {
+			letterboxd = append(letterboxd, letterboxdRating)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return RatingDistribution{}, RatingDistribution{}, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return newRatingDistribution(personal, total, 0.5), newRatingDistribution(letterboxd, total, 0.1), nil
+}
+
+// newRatingDistribution buckets ratings into step-wide buckets from 0.5 to 5.0.
+// total counts every movie in scope, so the rest are unrated
+func newRatingDistribution(ratings []float64, total int, step float64) RatingDistribution {
+	const low, high = 0.5, 5.0
+
+	buckets := make([]RatingBucket, int(math.Round((high-low)/step))+1)
+	for i := range buckets {
+		buckets[i].Rating = math.Round((low+float64(i)*step)*10) / 10
+	}
+
+	dist := RatingDistribution{
+		Buckets: buckets,
+		Rated:   len(ratings),
+		Unrated: total - len(ratings),
+	}
+	if len(ratings) == 0 {
+		return dist
+	}
+
+	var sum float64
+	for _, rating := range ratings {
+		// The small epsilon keeps 3.9 in the 3.9 bucket despite float error
+		i := int(math.Floor((rating-low)/step + 1e-9))
+		if i < 0 {
+			i = 0
+		} else if i >= len(buckets) {
+			i = len(buckets) - 1
+		}
+		buckets[i].Count++
+		sum += rating
+	}
+
+	mode := buckets[0]
+	for _, bucket := range buckets[1:] {
+		if bucket.Count > mode.Count {
+			mode = bucket
+		}
+	}
+	dist.Mode = mode.Rating
+
+	sorted := append([]float64{}, ratings...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		dist.Median = sorted[n/2]
+	} else {
+		dist.Median = round2((sorted[n/2-1] + sorted[n/2]) / 2)
+	}
+
+	// Population standard deviation: the collection is the whole population
+	mean := sum / float64(n)
+	var squares float64
+	for _, rating := range ratings {
+		squares += (rating - mean) * (rating - mean)
+	}
+	dist.StdDev = round2(math.Sqrt(squares / float64(n)))
+
+	return dist
+}
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"testing"
+)
+
+// bucketCounts maps the non-empty buckets of a distribution to their counts
+func bucketCounts(dist RatingDistribution) map[float64]int {
+	counts := make(map[float64]int)
+	for _, bucket := range dist.Buckets {
+		if bucket.Count > 0 {
+			counts[bucket.Rating] = bucket.Count
+		}
+	}
+	return counts
+}
+
+func TestRatingDistributionsEmpty(t *testing.T) {
+	db := newTestDB(t)
+
+	stats, err := db.GetStats(StatsFilter{})
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+
+	// The whole scale is there even without ratings
+	personal, letterboxd := stats.RatingDistribution, stats.LetterboxdRatingDistribution
+	if len(personal.Buckets) != 10 || personal.Buckets[0].Rating != 0.5 || personal.Buckets[9].Rating != 5 {
+		t.Errorf("personal buckets = %+v, want 0.5 to 5 in half stars", personal.Buckets)
+	}
+	if len(letterboxd.Buckets) != 46 || letterboxd.Buckets[34].Rating != 3.9 {
+		t.Errorf("letterboxd buckets = %+v, want 0.5 to 5 in tenths", letterboxd.Buckets)
+	}
+	if personal.Rated != 0 || personal.Unrated != 0 || personal.Median != 0 || personal.Mode != 0 || len(bucketCounts(personal)) != 0 {
+		t.Errorf("personal = %+v, want nothing rated", personal)
+	}
+}
+
+func TestRatingDistributions(t *testing.T) {
+	db := newTestDB(t)
+
+	addMovies(t, db,
+		Movie{LetterboxdID: "/film/paterson", Title: "Paterson", Rating: 4, LetterboxdRating: 3.9},
+		Movie{LetterboxdID: "/film/amelie", Title: "Amélie", Rating: 4, LetterboxdRating: 3.5},
+		Movie{LetterboxdID: "/film/ghost", Title: "Ghost", Rating: 3, LetterboxdRating: 4.1},
+		// Not scraped yet
+		Movie{LetterboxdID: "/film/heat", Title: "Heat", Rating: 5},
+		// Not rated
+		Movie{LetterboxdID: "/film/jaws", Title: "Jaws", LetterboxdRating: 3.9},
+	)
+
+	stats, err := db.GetStats(StatsFilter{})
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		got     RatingDistribution
+		counts  map[float64]int
+		rated   int
+		unrated int
+		median  float64
+		mode    float64
+		stdDev  float64
+	}{
+		// 3, 4, 4, 5
+		{"personal", stats.RatingDistribution, map[float64]int{3: 1, 4: 2, 5: 1}, 4, 1, 4, 4, 0.71},
+		// 3.5, 3.9, 3.9, 4.1
+		{"letterboxd", stats.LetterboxdRatingDistribution, map[float64]int{3.5: 1, 3.9: 2, 4.1: 1}, 4, 1, 3.9, 3.9, 0.22},
+	}
+	for _, tt := range tests {
+		counts := bucketCounts(tt.got)
+		if len(counts) != len(tt.counts) {
+			t.Errorf("%s: buckets = %v, want %v", tt.name, counts, tt.counts)
+		}
+		for rating, want := range tt.counts {
+			if counts[rating] != want {
+				t.Errorf("%s: bucket %v = %d, want %d", tt.name, rating, counts[rating], want)
+			}
+		}
+		if tt.got.Rated != tt.rated || tt.got.Unrated != tt.unrated {
+			t.Errorf("%s: rated %d, unrated %d, want %d, %d", tt.name, tt.got.Rated, tt.got.Unrated, tt.rated, tt.unrated)
+		}
+		if tt.got.Median != tt.median || tt.got.Mode != tt.mode || tt.got.StdDev != tt.stdDev {
+			t.Errorf("%s: median %v, mode %v, std dev %v, want %v, %v, %v",
+				tt.name, tt.got.Median, tt.got.Mode, tt.got.StdDev, tt.median, tt.mode, tt.stdDev)
+		}
+	}
+}
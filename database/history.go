@@ -0,0 +1,117 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// saveRatingHistory records the personal and Letterboxd ratings that differ
+// between the stored and the newly observed version of a movie. Ratings
+// added or removed (0 on either side) are not recorded
+func saveRatingHistory(tx *sql.Tx, old, updated Movie, observedAt time.Time) error {
+	query := `
+	INSERT INTO rating_history (movie_id, field, old_value, new_value, observed_at)
+	VALUES (?, ?, ?, ?, ?)
+	`
+
+	fields := []struct {
+		name       string
+		before, at float64
+	}{
+		{"rating", old.Rating, updated.Rating},
+		{"letterboxd_rating", old.LetterboxdRating, updated.LetterboxdRating},
+	}
+
+	for _, f := range fields {
+		if f.before == f.at || f.before == 0 || f.at == 0 {
+			continue
+		}
+		_, err := tx.Exec(query, updated.LetterboxdID, f.name, f.before, f.at, observedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return fmt.Errorf("failed to record %s change: %w", f.name, err)
+		}
+	}
+
+	return nil
+}
+
+// GetRatingHistory returns every recorded rating change of a movie, oldest first
+func (m *MovieDB) GetRatingHistory(letterboxdID string) ([]RatingChange, error) {
+	query := `
+	SELECT h.id, h.movie_id, m.title, h.field, h.old_value, h.new_value, h.observed_at
+	FROM rating_history h
+	JOIN movies m ON m.letterboxd_id = h.movie_id
+	WHERE h.movie_id = ?
+	ORDER BY h.observed_at ASC, h.id ASC
+	`
+
+	return m.queryRatingChanges(query, letterboxdID)
+}
+
+// GetReratedFilms returns the personal rating changes observed within
+// [from, to], most recent first. A zero from or to leaves that end open
+func (m *MovieDB) GetReratedFilms(from, to time.Time) ([]RatingChange, error) {
+	query := `
+	SELECT h.id, h.movie_id, m.title, h.field, h.old_value, h.new_value, h.observed_at
+	FROM rating_history h
+	JOIN movies m ON m.letterboxd_id = h.movie_id
+	WHERE h.field = 'rating'
+	  AND (? = '' OR h.observed_at >= ?)
+	  AND (? = '' OR h.observed_at <= ?)
+	ORDER BY h.observed_at DESC, h.id DESC
+	`
+
+	var fromStr, toStr string
+	if !from.IsZero() {
+		fromStr = from.UTC().Format(time.RFC3339)
+	}
+	if !to.IsZero() {
+		toStr = to.UTC().Format(time.RFC3339)
+	}
+
+	return m.queryRatingChanges(query, fromStr, fromStr, toStr, toStr)
+}
+
+// queryRatingChanges runs a rating_history SELECT and scans every row
+func (m *MovieDB) queryRatingChanges(query string, args ...interface{}) ([]RatingChange, error) {
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rating history: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []RatingChange
+	for rows.Next() {
+		var change RatingChange
+		var oldValue, newValue sql.NullFloat64
+		var observedAt string
+
+		err := rows.Scan(
+			&change.ID,
+			&change.LetterboxdID,
+			&change.Title,
+			&change.Field,
+			&oldValue,
+			&newValue,
+			&observedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rating history row: %w", err)
+		}
+
+		change.OldValue = oldValue.Float64
+		change.NewValue = newValue.Float64
+		if parsed, err := time.Parse(time.RFC3339, observedAt); err == nil {
+			change.ObservedAt = parsed
+		}
+
+		changes = append(changes, change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestRatingHistorySkipsAddedAndRemovedRatings(t *testing.T) {
+	db := newTestDB(t)
+
+	movie := Movie{LetterboxdID: "/film/paterson", Title: "Paterson", LetterboxdURL: "/film/paterson/"}
+	if err := db.AddMovie(movie); err != nil {
+		t.Fatalf("AddMovie: %v", err)
+	}
+
+	steps := []struct {
+		rating, letterboxdRating float64
+	}{
+		{4, 3.98},   // both added
+		{4.5, 3.98}, // re-rated
+		{4.5, 4.01}, // average moved
+		{0, 4.01},   // rating removed
+		{3, 4.01},   // rated again
+	}
+	for _, step := range steps {
+		movie.Rating = step.rating
+		movie.LetterboxdRating = step.letterboxdRating
+		if _, err := db.UpsertMovie(movie); err != nil {
+			t.Fatalf("UpsertMovie(%v, %v): %v", step.rating, step.letterboxdRating, err)
+		}
+	}
+	if err := db.SetRating("/film/paterson", 0); err != nil {
+		t.Fatalf("SetRating: %v", err)
+	}
+
+	history, err := db.GetRatingHistory("/film/paterson")
+	if err != nil {
+		t.Fatalf("GetRatingHistory: %v", err)
+	}
+
+	want := []RatingChange{
+		{Field: "rating", OldValue: 4, NewValue: 4.5},
+		{Field: "letterboxd_rating", OldValue: 3.98, NewValue: 4.01},
+	}
+	if len(history) != len(want) {
+		t.Fatalf("got %d history rows, want %d: %+v", len(history), len(want), history)
+	}
+	for i, got := range history {
+		if got.Field != want[i].Field || got.OldValue != want[i].OldValue || got.NewValue != want[i].NewValue {
+			t.Errorf("history[%d] = %s %v → %v, want %s %v → %v",
+				i, got.Field, got.OldValue, got.NewValue, want[i].Field, want[i].OldValue, want[i].NewValue)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ResolveMovieID finds the letterboxd_id of a movie, first by its short
+// boxd.it URI and then by title and release year. found is false when
+// neither matches
+func (m *MovieDB) ResolveMovieID(uri, title string, year int) (id string, found bool, err error) {
+	if uri != "" {
+		err = m.db.QueryRow("SELECT letterboxd_id FROM movies WHERE letterboxd_uri = ?", uri).Scan(&id)
+		if err == nil {
+			return id, true, nil
+		}
+		if err != sql.ErrNoRows {
+			return "", false, fmt.Errorf("failed to look up movie by uri: %w", err)
+		}
+	}
+
+	query := "SELECT letterboxd_id FROM movies WHERE title = ? COLLATE NOCASE AND year = ? LIMIT 1"
+	err = m.db.QueryRow(query, title, year).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to look up movie by title: %w", err)
+	}
+
+	return id, true, nil
+}
+
+// FindImportedMovie finds a movie created by the export importer and still
+// keyed by its boxd.it link, first by that link and then by title and
+// release year. found is false when no such movie exists
+func (m *MovieDB) FindImportedMovie(uri, title string, year int) (id string, found bool, err error) {
+	if uri != "" {
+		query := "SELECT letterboxd_id FROM movies WHERE letterboxd_uri = ? AND letterboxd_id LIKE 'boxd.it/%'"
+		err = m.db.QueryRow(query, uri).Scan(&id)
+		if err == nil {
+			return id, true, nil
+		}
+		if err != sql.ErrNoRows {
+			return "", false, fmt.Errorf("failed to look up imported movie by uri: %w", err)
+		}
+	}
+
+	query := `
+	SELECT letterboxd_id FROM movies
+	WHERE letterboxd_id LIKE 'boxd.it/%' AND title = ? COLLATE NOCASE AND year = ?
+	LIMIT 1
+	`
+	err = m.db.QueryRow(query, title, year).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to look up imported movie by title: %w", err)
+	}
+
+	return id, true, nil
+}
+
+// RekeyMovie moves a movie to a new letterboxd_id together with its
+// credits, classifications, viewings, reviews, rating history, list
+// entries and watchlist entry. Viewing and review keys embed the film id, so they are rewritten
+// too. Used to give films created by an export import their film slug
+func (m *MovieDB) RekeyMovie(oldID, newID string) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The children are moved after the movie, so check them at commit
+	if _, err := tx.Exec("PRAGMA defer_foreign_keys = ON"); err != nil {
+		return fmt.Errorf("failed to defer foreign keys: %w", err)
+	}
+
+	if _, err := tx.Exec("UPDATE movies SET letterboxd_id = ? WHERE letterboxd_id = ?", newID, oldID); err != nil {
+		return fmt.Errorf("failed to rekey movie %s: %w", oldID, err)
+	}
+
+	tables := []string{
+		"credits", "rating_history", "movie_genres", "movie_countries", "movie_languages",
+		"movie_studios", "movie_themes", "list_entries", "list_previous_entries",
+	}
+	for _, table := range tables {
+		if _, err := tx.Exec("UPDATE "+table+" SET movie_id = ? WHERE movie_id = ?", newID, oldID); err != nil {
+			return fmt.Errorf("failed to rekey %s of %s: %w", table, oldID, err)
+		}
+	}
+
+	// The watchlist has no foreign key; drop the old row if the slug is on it
+	if _, err := tx.Exec("UPDATE OR IGNORE watchlist SET letterboxd_id = ? WHERE letterboxd_id = ?", newID, oldID); err != nil {
+		return fmt.Errorf("failed to rekey watchlist entry of %s: %w", oldID, err)
+	}
+	if _, err := tx.Exec("DELETE FROM watchlist WHERE letterboxd_id = ?", oldID); err != nil {
+		return fmt.Errorf("failed to remove watchlist entry of %s: %w", oldID, err)
+	}
+
+	// Keys start with "<letterboxd_id>:", see ViewingKey and ReviewKey
+	_, err = tx.Exec(`
+	UPDATE viewings
+	SET movie_id = ?1, letterboxd_viewing_id = ?1 || substr(letterboxd_viewing_id, length(?2) + 1)
+	WHERE movie_id = ?2
+	`, newID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to rekey viewings of %s: %w", oldID, err)
+	}
+	_, err = tx.Exec(`
+	UPDATE reviews
+	SET movie_id = ?1, review_key = ?1 || substr(review_key, length(?2) + 1)
+	WHERE movie_id = ?2
+	`, newID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to rekey reviews of %s: %w", oldID, err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM movie_search WHERE letterboxd_id = ?", oldID); err != nil {
+		return fmt.Errorf("failed to clear search index: %w", err)
+	}
+	if err := reindexMovie(tx, newID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit rekey of %s: %w", oldID, err)
+	}
+
+	return nil
+}
+
+// SetLetterboxdURI records the short boxd.it link of a movie if it has none yet
+func (m *MovieDB) SetLetterboxdURI(letterboxdID, uri string) error {
+	query := "UPDATE movies SET letterboxd_uri = ? WHERE letterboxd_id = ? AND (letterboxd_uri IS NULL OR letterboxd_uri = '')"
+	if _, err := m.db.Exec(query, uri, letterboxdID); err != nil {
+		return fmt.Errorf("failed to set letterboxd uri: %w", err)
+	}
+	return nil
+}
+
+// SetRating updates the personal rating of a movie, recording the change
+// in rating_history when the stored rating differs
+func (m *MovieDB) SetRating(letterboxdID string, rating float64) error {
+	existing, found, err := m.GetMovie(letterboxdID)
+	if err != nil {
+		return err
+	}
+	if !found || existing.Rating == rating {
+		return nil
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE movies SET rating = ? WHERE letterboxd_id = ?", rating, letterboxdID); err != nil {
+		return fmt.Errorf("failed to set rating: %w", err)
+	}
+
+	updated := existing
+	updated.Rating = rating
+	if err := saveRatingHistory(tx, existing, updated, time.Now()); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
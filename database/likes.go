@@ -0,0 +1,93 @@
+package database
+
+import "fmt"
+
+// IsLiked reports whether a movie is stored and marked as liked
+func (m *MovieDB) IsLiked(letterboxdID string) (bool, error) {
+	var count int
+
+	query := "SELECT COUNT(*) FROM movies WHERE letterboxd_id = ? AND liked = 1"
+	if err := m.db.QueryRow(query, letterboxdID).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check if movie is liked: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// MarkLiked marks the given films as liked without touching any other
+// film, returning how many films changed. Ids not in the database are ignored
+func (m *MovieDB) MarkLiked(letterboxdIDs []string) (int, error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	changed := 0
+	for _, id := range letterboxdIDs {
+		res, err := tx.Exec("UPDATE movies SET liked = 1 WHERE letterboxd_id = ? AND liked = 0", id)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update like of %s: %w", id, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to update like of %s: %w", id, err)
+		}
+		changed += int(n)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return changed, nil
+}
+
+// SetLikedFilms marks exactly the given films as liked and every other film
+// as not liked, returning how many films changed. Ids not in the database
+// are ignored
+func (m *MovieDB) SetLikedFilms(letterboxdIDs []string) (int, error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	liked := make(map[string]bool, len(letterboxdIDs))
+	for _, id := range letterboxdIDs {
+		liked[id] = true
+	}
+
+	rows, err := tx.Query("SELECT letterboxd_id, liked FROM movies")
+	if err != nil {
+		return 0, fmt.Errorf("failed to query likes: %w", err)
+	}
+	changed := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		var wasLiked bool
+		if err := rows.Scan(&id, &wasLiked); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan like row: %w", err)
+		}
+		if wasLiked != liked[id] {
+			changed[id] = liked[id]
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	for id, isLiked := range changed {
+		if _, err := tx.Exec("UPDATE movies SET liked = ? WHERE letterboxd_id = ?", isLiked, id); err != nil {
+			return 0, fmt.Errorf("failed to update like of %s: %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(changed), nil
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+// likedFilms reports the liked state of each id
+func likedFilms(t *testing.T, db *MovieDB, ids ...string) map[string]bool {
+	t.Helper()
+	liked := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		isLiked, err := db.IsLiked(id)
+		if err != nil {
+			t.Fatalf("IsLiked(%s): %v", id, err)
+		}
+		liked[id] = isLiked
+	}
+	return liked
+}
+
+func TestLikedFilms(t *testing.T) {
+	db := newTestDB(t)
+	addMovies(t, db,
+		Movie{LetterboxdID: "/film/paterson", Title: "Paterson", Liked: true},
+		Movie{LetterboxdID: "/film/amelie", Title: "Amélie"},
+		Movie{LetterboxdID: "/film/ghost", Title: "Ghost"},
+	)
+	ids := []string{"/film/paterson", "/film/amelie", "/film/ghost"}
+
+	// An incremental sync only sees the newest likes, and never unsets the rest
+	changed, err := db.MarkLiked([]string{"/film/amelie", "/film/heat"})
+	if err != nil {
+		t.Fatalf("MarkLiked: %v", err)
+	}
+	if changed != 1 {
+		t.Errorf("MarkLiked changed %d films, want 1", changed)
+	}
+	want := map[string]bool{"/film/paterson": true, "/film/amelie": true, "/film/ghost": false}
+	if got := likedFilms(t, db, ids...); !reflect.DeepEqual(got, want) {
+		t.Errorf("after MarkLiked = %v, want %v", got, want)
+	}
+
+	// A full sync sets and clears likes to match the page
+	changed, err = db.SetLikedFilms([]string{"/film/amelie", "/film/ghost", "/film/heat"})
+	if err != nil {
+		t.Fatalf("SetLikedFilms: %v", err)
+	}
+	if changed != 2 {
+		t.Errorf("SetLikedFilms changed %d films, want 2", changed)
+	}
+	want = map[string]bool{"/film/paterson": false, "/film/amelie": true, "/film/ghost": true}
+	if got := likedFilms(t, db, ids...); !reflect.DeepEqual(got, want) {
+		t.Errorf("after SetLikedFilms = %v, want %v", got, want)
+	}
+	if liked, _ := db.IsLiked("/film/heat"); liked {
+		t.Error("film not in the collection reported as liked")
+	}
+
+	// Nothing left to change
+	if changed, err := db.SetLikedFilms([]string{"/film/amelie", "/film/ghost"}); err != nil || changed != 0 {
+		t.Errorf("repeated SetLikedFilms changed %d films, err %v, want 0", changed, err)
+	}
+}
@@ -0,0 +1,302 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// List is one of the user's Letterboxd lists. UpdatedAt is when it was
+// last edited on Letterboxd; SyncedAt is when its entries were last
+// replaced and PreviousSyncedAt the replacement before that
+type List struct {
+	Slug             string    `json:"slug"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description"`
+	URL              string    `json:"url"`
+	Ranked           bool      `json:"ranked"`
+	FilmCount        int       `json:"film_count"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	SyncedAt         time.Time `json:"synced_at"`
+	PreviousSyncedAt time.Time `json:"previous_synced_at"`
+}
+
+// ListEntry is a film on a list. Position starts at 1; Watched and Rating
+// come from the movies table when the film is in the collection
+type ListEntry struct {
+	Position      int     `json:"position"`
+	LetterboxdID  string  `json:"letterboxd_id"`
+	Title         string  `json:"title"`
+	LetterboxdURL string  `json:"letterboxd_url"`
+	Watched       bool    `json:"watched"`
+	Rating        float64 `json:"rating"`
+}
+
+// ListMove is a film whose position changed between two syncs of a list
+type ListMove struct {
+	Entry       ListEntry `json:"entry"`
+	OldPosition int       `json:"old_position"`
+}
+
+// ListDiff compares a list's entries with the entries it had before the
+// last sync that changed them. Removed entries carry their old position
+type ListDiff struct {
+	List    List        `json:"list"`
+	Added   []ListEntry `json:"added"`
+	Removed []ListEntry `json:"removed"`
+	Moved   []ListMove  `json:"moved"`
+}
+
+// SaveList inserts or updates a list. When entries is nil only the list
+// details are saved; otherwise the stored entries become the previous
+// entries and are replaced with entries
+func (m *MovieDB) SaveList(list List, entries []ListEntry) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM lists WHERE slug = ?", list.Slug).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check if list exists: %w", err)
+	}
+
+	// A list seen for the first time gets its sync time even without entries
+	_, err = tx.Exec(`
+	INSERT INTO lists (slug, name, description, url, ranked, film_count, updated_at, synced_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(slug) DO UPDATE SET
+		name = excluded.name,
+		description = excluded.description,
+		url = excluded.url,
+		ranked = excluded.ranked,
+		film_count = excluded.film_count,
+		updated_at = excluded.updated_at
+	`, list.Slug, list.Name, nullString(list.Description), list.URL, list.Ranked, list.FilmCount,
+		nullTime(list.UpdatedAt), time.Now().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to save list %s: %w", list.Slug, err)
+	}
+
+	if entries != nil {
+		if err := replaceListEntries(tx, list.Slug, entries, count > 0); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// replaceListEntries writes the entries of a list inside the given
+// transaction. When archive is set the current entries are first moved to
+// list_previous_entries, otherwise the list is new and has none
+func replaceListEntries(tx *sql.Tx, slug string, entries []ListEntry, archive bool) error {
+	if archive {
+		if _, err := tx.Exec("DELETE FROM list_previous_entries WHERE list_id = ?", slug); err != nil {
+			return fmt.Errorf("failed to clear previous entries of list %s: %w", slug, err)
+		}
+
+		_, err := tx.Exec(`
+		INSERT INTO list_previous_entries (list_id, position, movie_id, title, letterboxd_url)
+		SELECT list_id, position, movie_id, title, letterboxd_url FROM list_entries WHERE list_id = ?
+		`, slug)
+		if err != nil {
+			return fmt.Errorf("failed to archive entries of list %s: %w", slug, err)
+		}
+
+		if _, err := tx.Exec("DELETE FROM list_entries WHERE list_id = ?", slug); err != nil {
+			return fmt.Errorf("failed to clear entries of list %s: %w", slug, err)
+		}
+
+		_, err = tx.Exec("UPDATE lists SET previous_synced_at = synced_at, synced_at = ? WHERE slug = ?",
+			time.Now().Format(time.RFC3339), slug)
+		if err != nil {
+			return fmt.Errorf("failed to update sync time of list %s: %w", slug, err)
+		}
+	}
+
+	stmt, err := tx.Prepare(`
+	INSERT INTO list_entries (list_id, position, movie_id, title, letterboxd_url)
+	VALUES (?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare list entry statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		if _, err := stmt.Exec(slug, entry.Position, entry.LetterboxdID, entry.Title, entry.LetterboxdURL); err != nil {
+			return fmt.Errorf("failed to save entry %d of list %s: %w", entry.Position, slug, err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteListsExcept removes every stored list whose slug is not in slugs,
+// with its entries
+func (m *MovieDB) DeleteListsExcept(slugs []string) error {
+	keep := make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		keep[slug] = true
+	}
+
+	lists, err := m.GetLists()
+	if err != nil {
+		return err
+	}
+	for _, list := range lists {
+		if keep[list.Slug] {
+			continue
+		}
+		if _, err := m.db.Exec("DELETE FROM lists WHERE slug = ?", list.Slug); err != nil {
+			return fmt.Errorf("failed to delete list %s: %w", list.Slug, err)
+		}
+	}
+
+	return nil
+}
+
+// GetList retrieves a list by slug. found is false if no such list exists
+func (m *MovieDB) GetList(slug string) (list List, found bool, err error) {
+	lists, err := m.queryLists("WHERE slug = ?", slug)
+	if err != nil || len(lists) == 0 {
+		return list, false, err
+	}
+	return lists[0], true, nil
+}
+
+// GetLists returns every stored list, by name
+func (m *MovieDB) GetLists() ([]List, error) {
+	return m.queryLists("ORDER BY name COLLATE NOCASE ASC")
+}
+
+// queryLists selects lists with the given WHERE/ORDER BY clause
+func (m *MovieDB) queryLists(clause string, args ...interface{}) ([]List, error) {
+	rows, err := m.db.Query(`
+	SELECT slug, name, description, url, ranked, film_count, updated_at, synced_at, previous_synced_at
+	FROM lists
+	`+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lists: %w", err)
+	}
+	defer rows.Close()
+
+	lists := []List{}
+	for rows.Next() {
+		var list List
+		var description, updatedAt, previousSyncedAt sql.NullString
+		var syncedAt string
+		err := rows.Scan(&list.Slug, &list.Name, &description, &list.URL, &list.Ranked, &list.FilmCount,
+			&updatedAt, &syncedAt, &previousSyncedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan list row: %w", err)
+		}
+
+		list.Description = description.String
+		list.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt.String)
+		list.SyncedAt, _ = time.Parse(time.RFC3339, syncedAt)
+		list.PreviousSyncedAt, _ = time.Parse(time.RFC3339, previousSyncedAt.String)
+
+		lists = append(lists, list)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return lists, nil
+}
+
+// GetListEntries returns the films of a list in list order
+func (m *MovieDB) GetListEntries(slug string) ([]ListEntry, error) {
+	return m.queryListEntries("list_entries", slug)
+}
+
+// queryListEntries returns the entries of a list from table
+// (list_entries or list_previous_entries) in list order
+func (m *MovieDB) queryListEntries(table, slug string) ([]ListEntry, error) {
+	rows, err := m.db.Query(`
+	SELECT e.position, e.movie_id, e.title, e.letterboxd_url, m.letterboxd_id IS NOT NULL, COALESCE(m.rating, 0)
+	FROM `+table+` e
+	LEFT JOIN movies m ON m.letterboxd_id = e.movie_id
+	WHERE e.list_id = ?
+	ORDER BY e.position ASC
+	`, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query list entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []ListEntry{}
+	for rows.Next() {
+		var entry ListEntry
+		err := rows.Scan(&entry.Position, &entry.LetterboxdID, &entry.Title, &entry.LetterboxdURL,
+			&entry.Watched, &entry.Rating)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan list entry row: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+// DiffList compares the entries of a list with its previous entries.
+// A list synced only once has no previous entries, so everything is added
+func (m *MovieDB) DiffList(slug string) (ListDiff, error) {
+	diff := ListDiff{Added: []ListEntry{}, Removed: []ListEntry{}, Moved: []ListMove{}}
+
+	list, found, err := m.GetList(slug)
+	if err != nil {
+		return diff, err
+	}
+	if !found {
+		return diff, fmt.Errorf("list %s not found", slug)
+	}
+	diff.List = list
+
+	current, err := m.queryListEntries("list_entries", slug)
+	if err != nil {
+		return diff, err
+	}
+	previous, err := m.queryListEntries("list_previous_entries", slug)
+	if err != nil {
+		return diff, err
+	}
+
+	oldPositions := make(map[string]int, len(previous))
+	for _, entry := range previous {
+		if _, seen := oldPositions[entry.LetterboxdID]; !seen {
+			oldPositions[entry.LetterboxdID] = entry.Position
+		}
+	}
+
+	onList := make(map[string]bool, len(current))
+	for _, entry := range current {
+		onList[entry.LetterboxdID] = true
+		oldPosition, wasOnList := oldPositions[entry.LetterboxdID]
+		switch {
+		case !wasOnList:
+			diff.Added = append(diff.Added, entry)
+		case oldPosition != entry.Position:
+			diff.Moved = append(diff.Moved, ListMove{Entry: entry, OldPosition: oldPosition})
+		}
+	}
+	for _, entry := range previous {
+		if !onList[entry.LetterboxdID] {
+			diff.Removed = append(diff.Removed, entry)
+		}
+	}
+
+	return diff, nil
+}
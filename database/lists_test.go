@@ -0,0 +1,81 @@
+package database
+
+import (
+	"testing"
+)
+
+// entryIDs returns the film ids of list entries, in order
+func entryIDs(entries []ListEntry) []string {
+	ids := []string{}
+	for _, entry := range entries {
+		ids = append(ids, entry.LetterboxdID)
+	}
+	return ids
+}
+
+func TestDiffList(t *testing.T) {
+	db := newTestDB(t)
+
+	list := List{Slug: "best-of-2024", Name: "Best of 2024", URL: "https://letterboxd.com/bob/list/best-of-2024/", Ranked: true}
+	entry := func(position int, slug, title string) ListEntry {
+		return ListEntry{Position: position, LetterboxdID: "/film/" + slug, Title: title, LetterboxdURL: "/film/" + slug + "/"}
+	}
+
+	// First sync: no previous snapshot, so every film is added
+	if err := db.SaveList(list, []ListEntry{entry(1, "paterson", "Paterson"), entry(2, "amelie", "Amélie")}); err != nil {
+		t.Fatalf("SaveList: %v", err)
+	}
+	diff, err := db.DiffList(list.Slug)
+	if err != nil {
+		t.Fatalf("DiffList: %v", err)
+	}
+	if got := entryIDs(diff.Added); len(got) != 2 || got[0] != "/film/paterson" || got[1] != "/film/amelie" {
+		t.Errorf("added = %v, want both films", got)
+	}
+	if len(diff.Removed) != 0 || len(diff.Moved) != 0 {
+		t.Errorf("removed %v, moved %v on the first sync", diff.Removed, diff.Moved)
+	}
+	if diff.List.Name != list.Name || !diff.List.PreviousSyncedAt.IsZero() {
+		t.Errorf("list = %+v", diff.List)
+	}
+
+	// Second sync: Ghost and Jaws added, Amélie removed, Paterson moved down
+	err = db.SaveList(list, []ListEntry{entry(1, "ghost", "Ghost"), entry(2, "paterson", "Paterson"), entry(3, "jaws", "Jaws")})
+	if err != nil {
+		t.Fatalf("SaveList: %v", err)
+	}
+	diff, err = db.DiffList(list.Slug)
+	if err != nil {
+		t.Fatalf("DiffList: %v", err)
+	}
+	if got := entryIDs(diff.Added); len(got) != 2 || got[0] != "/film/ghost" || got[1] != "/film/jaws" {
+		t.Errorf("added = %v, want [/film/ghost /film/jaws]", got)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].LetterboxdID != "/film/amelie" || diff.Removed[0].Position != 2 {
+		t.Errorf("removed = %+v, want Amélie at its old position 2", diff.Removed)
+	}
+	if len(diff.Moved) != 1 || diff.Moved[0].Entry.LetterboxdID != "/film/paterson" ||
+		diff.Moved[0].OldPosition != 1 || diff.Moved[0].Entry.Position != 2 {
+		t.Errorf("moved = %+v, want Paterson from 1 to 2", diff.Moved)
+	}
+	if diff.List.PreviousSyncedAt.IsZero() {
+		t.Error("previous sync time not set")
+	}
+
+	// Saving only the details keeps the snapshot to compare with
+	list.Description = "Updated."
+	if err := db.SaveList(list, nil); err != nil {
+		t.Fatalf("SaveList: %v", err)
+	}
+	diff, err = db.DiffList(list.Slug)
+	if err != nil {
+		t.Fatalf("DiffList: %v", err)
+	}
+	if len(diff.Added) != 2 || len(diff.Removed) != 1 || len(diff.Moved) != 1 || diff.List.Description != "Updated." {
+		t.Errorf("diff after a details-only save = %+v", diff)
+	}
+
+	if _, err := db.DiffList("no-such-list"); err == nil {
+		t.Error("DiffList of an unknown list succeeded")
+	}
+}
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS movies (
+	letterboxd_id TEXT PRIMARY KEY,
+	title TEXT NOT NULL,
+	year INTEGER,
+	letterboxd_url TEXT NOT NULL,
+	rating REAL,
+	letterboxd_rating REAL,
+	length INTEGER,
+	date_added TEXT NOT NULL,
+	poster_url TEXT,
+	director TEXT,
+	cast TEXT,
+	writers TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_title ON movies(title);
+CREATE INDEX IF NOT EXISTS idx_rating ON movies(rating);
+CREATE INDEX IF NOT EXISTS idx_year ON movies(year);
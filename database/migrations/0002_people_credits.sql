@@ -0,0 +1,44 @@
+CREATE TABLE IF NOT EXISTS people (
+	slug TEXT PRIMARY KEY,
+	name TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS credits (
+	movie_id TEXT NOT NULL REFERENCES movies(letterboxd_id) ON DELETE CASCADE,
+	person_id TEXT NOT NULL REFERENCES people(slug) ON DELETE CASCADE,
+	role TEXT NOT NULL,
+	billing_order INTEGER NOT NULL DEFAULT 0,
+	character_name TEXT,
+	PRIMARY KEY (movie_id, person_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS idx_credits_person ON credits(person_id, role);
+CREATE INDEX IF NOT EXISTS idx_credits_role ON credits(role);
+
+-- Backfill from the legacy comma-joined columns. The real Letterboxd slug
+-- is not known for these rows, so one is derived from the name; the next
+-- detail scrape of the film replaces them with proper credits.
+CREATE TEMP TABLE legacy_credits AS
+WITH RECURSIVE split(movie_id, role, item, rest, pos) AS (
+	SELECT letterboxd_id, 'director', '', director || ',', -1 FROM movies WHERE director IS NOT NULL AND director != ''
+	UNION ALL
+	SELECT letterboxd_id, 'actor', '', "cast" || ',', -1 FROM movies WHERE "cast" IS NOT NULL AND "cast" != ''
+	UNION ALL
+	SELECT letterboxd_id, 'writer', '', writers || ',', -1 FROM movies WHERE writers IS NOT NULL AND writers != ''
+	UNION ALL
+	SELECT movie_id, role,
+		trim(substr(rest, 1, instr(rest, ',') - 1)),
+		substr(rest, instr(rest, ',') + 1),
+		pos + 1
+	FROM split WHERE rest != ''
+)
+SELECT movie_id, role, item AS name, lower(replace(item, ' ', '-')) AS slug, pos
+FROM split WHERE pos >= 0 AND item != '';
+
+INSERT OR IGNORE INTO people (slug, name)
+SELECT slug, name FROM legacy_credits;
+
+INSERT OR IGNORE INTO credits (movie_id, person_id, role, billing_order)
+SELECT movie_id, slug, role, pos FROM legacy_credits;
+
+DROP TABLE legacy_credits;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS viewings (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	letterboxd_viewing_id TEXT NOT NULL UNIQUE,
+	movie_id TEXT NOT NULL REFERENCES movies(letterboxd_id) ON DELETE CASCADE,
+	watched_date TEXT NOT NULL,
+	rewatch INTEGER NOT NULL DEFAULT 0,
+	rating REAL,
+	review_url TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_viewings_movie ON viewings(movie_id);
+CREATE INDEX IF NOT EXISTS idx_viewings_watched_date ON viewings(watched_date);
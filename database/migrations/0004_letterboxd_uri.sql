@@ -0,0 +1,5 @@
+-- Short boxd.it link for each film, used to match rows from the
+-- official Letterboxd data export
+ALTER TABLE movies ADD COLUMN letterboxd_uri TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_letterboxd_uri ON movies(letterboxd_uri);
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS sync_state (
+	username TEXT PRIMARY KEY,
+	last_synced_at TEXT,
+	last_full_sync_at TEXT
+);
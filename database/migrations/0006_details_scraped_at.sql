@@ -0,0 +1,6 @@
+-- When the film's detail page was last scraped, so refreshes can
+-- re-scrape stale metadata. Rows with details were scraped at import time
+ALTER TABLE movies ADD COLUMN details_scraped_at TEXT;
+
+UPDATE movies SET details_scraped_at = date_added
+WHERE letterboxd_rating > 0 OR length > 0 OR (director IS NOT NULL AND director != '');
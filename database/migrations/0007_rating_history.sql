@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS rating_history (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	movie_id TEXT NOT NULL REFERENCES movies(letterboxd_id) ON DELETE CASCADE,
+	field TEXT NOT NULL,
+	old_value REAL,
+	new_value REAL,
+	observed_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rating_history_movie ON rating_history(movie_id, observed_at);
+CREATE INDEX IF NOT EXISTS idx_rating_history_field ON rating_history(field, observed_at);
@@ -0,0 +1,69 @@
+-- Lookup tables for the classifications shown on a film page, keyed by
+-- the Letterboxd slug (e.g. /films/genre/science-fiction/)
+CREATE TABLE IF NOT EXISTS genres (
+	slug TEXT PRIMARY KEY,
+	name TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS countries (
+	slug TEXT PRIMARY KEY,
+	name TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS languages (
+	slug TEXT PRIMARY KEY,
+	name TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS studios (
+	slug TEXT PRIMARY KEY,
+	name TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS themes (
+	slug TEXT PRIMARY KEY,
+	name TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS movie_genres (
+	movie_id TEXT NOT NULL REFERENCES movies(letterboxd_id) ON DELETE CASCADE,
+	genre_id TEXT NOT NULL REFERENCES genres(slug) ON DELETE CASCADE,
+	position INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (movie_id, genre_id)
+);
+
+CREATE TABLE IF NOT EXISTS movie_countries (
+	movie_id TEXT NOT NULL REFERENCES movies(letterboxd_id) ON DELETE CASCADE,
+	country_id TEXT NOT NULL REFERENCES countries(slug) ON DELETE CASCADE,
+	position INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (movie_id, country_id)
+);
+
+-- Every spoken language of a film; is_primary marks the primary one
+CREATE TABLE IF NOT EXISTS movie_languages (
+	movie_id TEXT NOT NULL REFERENCES movies(letterboxd_id) ON DELETE CASCADE,
+	language_id TEXT NOT NULL REFERENCES languages(slug) ON DELETE CASCADE,
+	is_primary INTEGER NOT NULL DEFAULT 0,
+	position INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (movie_id, language_id)
+);
+
+CREATE TABLE IF NOT EXISTS movie_studios (
+	movie_id TEXT NOT NULL REFERENCES movies(letterboxd_id) ON DELETE CASCADE,
+	studio_id TEXT NOT NULL REFERENCES studios(slug) ON DELETE CASCADE,
+	position INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (movie_id, studio_id)
+);
+
+CREATE TABLE IF NOT EXISTS movie_themes (
+	movie_id TEXT NOT NULL REFERENCES movies(letterboxd_id) ON DELETE CASCADE,
+	theme_id TEXT NOT NULL REFERENCES themes(slug) ON DELETE CASCADE,
+	position INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (movie_id, theme_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_genres_genre ON movie_genres(genre_id);
+CREATE INDEX IF NOT EXISTS idx_movie_countries_country ON movie_countries(country_id);
+CREATE INDEX IF NOT EXISTS idx_movie_languages_language ON movie_languages(language_id, is_primary);
+CREATE INDEX IF NOT EXISTS idx_movie_studios_studio ON movie_studios(studio_id);
+CREATE INDEX IF NOT EXISTS idx_movie_themes_theme ON movie_themes(theme_id);
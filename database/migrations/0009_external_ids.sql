@@ -0,0 +1,6 @@
+ALTER TABLE movies ADD COLUMN tmdb_id INTEGER;
+ALTER TABLE movies ADD COLUMN tmdb_type TEXT;
+ALTER TABLE movies ADD COLUMN imdb_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_movies_tmdb ON movies(tmdb_type, tmdb_id);
+CREATE INDEX IF NOT EXISTS idx_movies_imdb ON movies(imdb_id);
@@ -0,0 +1,24 @@
+ALTER TABLE movies ADD COLUMN synopsis TEXT;
+
+-- Full-text index over each film's title, credited people, synopsis and
+-- review text. remove_diacritics lets "Amelie" match "Amélie". Rows are
+-- rewritten by the app whenever a film is saved (see reindexMovie)
+CREATE VIRTUAL TABLE IF NOT EXISTS movie_search USING fts5(
+	letterboxd_id UNINDEXED,
+	title,
+	people,
+	synopsis,
+	reviews,
+	tokenize = "unicode61 remove_diacritics 2"
+);
+
+INSERT INTO movie_search (letterboxd_id, title, people, synopsis, reviews)
+SELECT m.letterboxd_id, m.title,
+	COALESCE((
+		SELECT group_concat(p.name, ' ')
+		FROM credits c
+		JOIN people p ON p.slug = c.person_id
+		WHERE c.movie_id = m.letterboxd_id
+	), ''),
+	'', ''
+FROM movies m;
@@ -0,0 +1,26 @@
+-- Films on the user's watchlist, kept apart from the watched films in
+-- movies. Columns mirror movies minus the personal rating; date_added is
+-- when a sync first saw the film on the watchlist, position its place in
+-- Letterboxd's default order (1 = most recently added)
+CREATE TABLE IF NOT EXISTS watchlist (
+	letterboxd_id TEXT PRIMARY KEY,
+	title TEXT NOT NULL,
+	year INTEGER,
+	letterboxd_url TEXT NOT NULL,
+	letterboxd_rating REAL,
+	length INTEGER,
+	date_added TEXT NOT NULL,
+	poster_url TEXT,
+	director TEXT,
+	"cast" TEXT,
+	writers TEXT,
+	letterboxd_uri TEXT,
+	details_scraped_at TEXT,
+	tmdb_id INTEGER,
+	tmdb_type TEXT,
+	imdb_id TEXT,
+	synopsis TEXT,
+	position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_position ON watchlist(position);
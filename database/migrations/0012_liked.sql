@@ -0,0 +1,4 @@
+-- Whether the user gave the film a heart on Letterboxd
+ALTER TABLE movies ADD COLUMN liked INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_movies_liked ON movies(liked);
@@ -0,0 +1,38 @@
+-- The user's own Letterboxd lists, keyed by the slug in the list URL
+-- (/username/list/best-of-2024/). updated_at is Letterboxd's last edit
+-- time; synced_at and previous_synced_at are when the entries were last
+-- replaced and the time before that
+CREATE TABLE IF NOT EXISTS lists (
+	slug TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	description TEXT,
+	url TEXT NOT NULL,
+	ranked INTEGER NOT NULL DEFAULT 0,
+	film_count INTEGER NOT NULL DEFAULT 0,
+	updated_at TEXT,
+	synced_at TEXT NOT NULL,
+	previous_synced_at TEXT
+);
+
+-- Films of a list in list order. movie_id is the film's letterboxd_id; it is
+-- not a foreign key because lists can hold films the user has not watched
+CREATE TABLE IF NOT EXISTS list_entries (
+	list_id TEXT NOT NULL REFERENCES lists(slug) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	movie_id TEXT NOT NULL,
+	title TEXT NOT NULL,
+	letterboxd_url TEXT NOT NULL,
+	PRIMARY KEY (list_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_list_entries_movie ON list_entries(movie_id);
+
+-- The entries as they were before the last sync that changed them, for diffs
+CREATE TABLE IF NOT EXISTS list_previous_entries (
+	list_id TEXT NOT NULL REFERENCES lists(slug) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	movie_id TEXT NOT NULL,
+	title TEXT NOT NULL,
+	letterboxd_url TEXT NOT NULL,
+	PRIMARY KEY (list_id, position)
+);
@@ -0,0 +1,16 @@
+-- The user's reviews. review_key is built by ReviewKey from the film and
+-- watched date, like viewings, so scraped and imported copies of a review
+-- share one row; undated reviews are keyed by their URL
+CREATE TABLE IF NOT EXISTS reviews (
+	review_key TEXT PRIMARY KEY,
+	movie_id TEXT NOT NULL REFERENCES movies(letterboxd_id) ON DELETE CASCADE,
+	url TEXT,
+	watched_date TEXT,
+	rating REAL,
+	body TEXT NOT NULL,
+	like_count INTEGER NOT NULL DEFAULT 0,
+	spoiler INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviews_movie ON reviews(movie_id);
+CREATE INDEX IF NOT EXISTS idx_reviews_watched ON reviews(watched_date);
@@ -0,0 +1,14 @@
+-- User tags on diary entries, e.g. "theatre" or "with-family"
+CREATE TABLE IF NOT EXISTS tags (
+	slug TEXT PRIMARY KEY,
+	name TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS viewing_tags (
+	viewing_id INTEGER NOT NULL REFERENCES viewings(id) ON DELETE CASCADE,
+	tag_id TEXT NOT NULL REFERENCES tags(slug) ON DELETE CASCADE,
+	position INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (viewing_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_viewing_tags_tag ON viewing_tags(tag_id);
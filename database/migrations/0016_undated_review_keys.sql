@@ -0,0 +1,6 @@
+-- Undated reviews were keyed by their URL, which differs between the
+-- export (boxd.it link) and the site, so both copies were stored.
+-- Key them by film instead; when a film has both, one copy is kept
+UPDATE OR REPLACE reviews
+SET review_key = movie_id || ':undated'
+WHERE watched_date IS NULL;
@@ -0,0 +1,177 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// newTestDB opens a fully migrated database in a temporary directory
+func newTestDB(t *testing.T) *MovieDB {
+	t.Helper()
+	db, err := NewMovieDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewMovieDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// latestVersion returns the version of the newest embedded migration
+func latestVersion(t *testing.T) int {
+	t.Helper()
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+func TestMigrateBaselineDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.db")
+
+	// The schema created before migrations existed
+	raw, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	_, err = raw.Exec(`
+	CREATE TABLE movies (
+		letterboxd_id TEXT PRIMARY KEY,
+		title TEXT NOT NULL,
+		year INTEGER,
+		letterboxd_url TEXT NOT NULL,
+		rating REAL,
+		letterboxd_rating REAL,
+		length INTEGER,
+		date_added TEXT NOT NULL,
+		poster_url TEXT,
+		director TEXT,
+		cast TEXT,
+		writers TEXT
+	);
+	INSERT INTO movies (letterboxd_id, title, year, letterboxd_url, rating, letterboxd_rating, length, date_added, director)
+	VALUES ('paterson', 'Paterson', 2016, '/film/paterson/', 4.5, 3.98, 118, '2024-01-02T03:04:05Z', 'Jim Jarmusch');
+	`)
+	if err != nil {
+		t.Fatalf("create baseline schema: %v", err)
+	}
+	raw.Close()
+
+	db, err := NewMovieDB(path)
+	if err != nil {
+		t.Fatalf("NewMovieDB: %v", err)
+	}
+	defer db.Close()
+
+	version, err := db.SchemaVersion()
+	if err != nil {
+		t.Fatalf("SchemaVersion: %v", err)
+	}
+	if want := latestVersion(t); version != want {
+		t.Errorf("version = %d, want %d", version, want)
+	}
+
+	movie, found, err := db.GetMovie("paterson")
+	if err != nil || !found {
+		t.Fatalf("GetMovie = found %v, err %v", found, err)
+	}
+	if movie.Title != "Paterson" || movie.Year != 2016 || movie.Rating != 4.5 || movie.Length != 118 || movie.Director != "Jim Jarmusch" {
+		t.Errorf("movie = %+v, baseline data not kept", movie)
+	}
+
+	// Tables from later migrations exist
+	if _, err := db.GetViewings("paterson"); err != nil {
+		t.Errorf("GetViewings: %v", err)
+	}
+}
+
+func TestMigrateSchemaTooNew(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := NewMovieDB(path)
+	if err != nil {
+		t.Fatalf("NewMovieDB: %v", err)
+	}
+	_, err = db.db.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, 'future', '2030-01-01T00:00:00Z')",
+		latestVersion(t)+1)
+	if err != nil {
+		t.Fatalf("insert future version: %v", err)
+	}
+	db.Close()
+
+	db, err = NewMovieDB(path)
+	if err == nil {
+		db.Close()
+	}
+	if !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("err = %v, want ErrSchemaTooNew", err)
+	}
+}
+
+func TestMigrateRollsBackFailedMigration(t *testing.T) {
+	db := newTestDB(t)
+	latest := latestVersion(t)
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	migrations = append(migrations,
+		migration{Version: latest + 1, Name: "good", SQL: "CREATE TABLE rollback_probe (id INTEGER)"},
+		migration{Version: latest + 2, Name: "bad", SQL: "CREATE TABLE broken ("},
+	)
+
+	if err := db.applyMigrations(migrations); err == nil {
+		t.Fatal("applyMigrations succeeded with a broken migration")
+	}
+
+	version, err := db.SchemaVersion()
+	if err != nil {
+		t.Fatalf("SchemaVersion: %v", err)
+	}
+	if version != latest {
+		t.Errorf("version = %d, want %d", version, latest)
+	}
+
+	var count int
+	if err := db.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'rollback_probe'").Scan(&count); err != nil {
+		t.Fatalf("query sqlite_master: %v", err)
+	}
+	if count != 0 {
+		t.Error("table from the earlier pending migration was kept")
+	}
+}
+
+func TestMigrateReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := NewMovieDB(path)
+	if err != nil {
+		t.Fatalf("NewMovieDB: %v", err)
+	}
+	if err := db.AddMovie(Movie{LetterboxdID: "/film/paterson", Title: "Paterson", LetterboxdURL: "/film/paterson/"}); err != nil {
+		t.Fatalf("AddMovie: %v", err)
+	}
+	db.Close()
+
+	for i := 0; i < 2; i++ {
+		db, err = NewMovieDB(path)
+		if err != nil {
+			t.Fatalf("reopen %d: %v", i, err)
+		}
+
+		var applied int
+		if err := db.db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&applied); err != nil {
+			t.Fatalf("count migrations: %v", err)
+		}
+		migrations, _ := loadMigrations()
+		if applied != len(migrations) {
+			t.Errorf("reopen %d: %d migrations recorded, want %d", i, applied, len(migrations))
+		}
+
+		if _, found, err := db.GetMovie("/film/paterson"); err != nil || !found {
+			t.Errorf("reopen %d: GetMovie = found %v, err %v", i, found, err)
+		}
+		db.Close()
+	}
+}
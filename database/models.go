@@ -18,4 +18,86 @@ type Movie struct {
 	Director         string    `json:"director"`
 	Cast             string    `json:"cast"`
 	Writers          string    `json:"writers"`
+	LetterboxdURI    string    `json:"letterboxd_uri"`
+	DetailsScrapedAt time.Time `json:"details_scraped_at"`
+	TmdbID           int       `json:"tmdb_id"`
+	TmdbType         string    `json:"tmdb_type"`
+	ImdbID           string    `json:"imdb_id"`
+	Liked            bool      `json:"liked"`
+	Synopsis         string    `json:"synopsis"`
+	Credits          []Credit  `json:"credits,omitempty"`
+	Genres           []Term    `json:"genres,omitempty"`
+	Countries        []Term    `json:"countries,omitempty"`
+	PrimaryLanguage  *Term     `json:"primary_language,omitempty"`
+	Languages        []Term    `json:"languages,omitempty"`
+	Studios          []Term    `json:"studios,omitempty"`
+	Themes           []Term    `json:"themes,omitempty"`
+}
+
+// Term is an entry of one of the film classification lookups (genre,
+// country, language, studio or theme). Slug is the Letterboxd slug,
+// e.g. "science-fiction"
+type Term struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// Credit roles stored in the credits table
+const (
+	RoleDirector = "director"
+	RoleActor    = "actor"
+	RoleWriter   = "writer"
+)
+
+// Credit links a person to a movie in a given role.
+// PersonSlug is the Letterboxd person slug, e.g. "tilda-swinton"
+type Credit struct {
+	PersonSlug    string `json:"person_slug"`
+	Name          string `json:"name"`
+	Role          string `json:"role"`
+	BillingOrder  int    `json:"billing_order"`
+	CharacterName string `json:"character_name"`
+}
+
+// Viewing is a single dated watch of a movie from the user's diary.
+// A movie can have many viewings; Rating is the rating given at that viewing.
+// Tags are the user's tags on the entry; nil leaves the stored tags unchanged
+type Viewing struct {
+	ID                  int64     `json:"id"`
+	LetterboxdViewingID string    `json:"letterboxd_viewing_id"`
+	LetterboxdID        string    `json:"letterboxd_id"`
+	WatchedDate         time.Time `json:"watched_date"`
+	Rewatch             bool      `json:"rewatch"`
+	Rating              float64   `json:"rating"`
+	ReviewURL           string    `json:"review_url"`
+	Tags                []Term    `json:"tags"`
+}
+
+// SyncState records when a Letterboxd user was last synced.
+// LastFullSyncAt is only set by syncs that walked every page
+type SyncState struct {
+	Username       string    `json:"username"`
+	LastSyncedAt   time.Time `json:"last_synced_at"`
+	LastFullSyncAt time.Time `json:"last_full_sync_at"`
+}
+
+// FieldChange describes one field of a movie that changed during a refresh
+type FieldChange struct {
+	LetterboxdID string `json:"letterboxd_id"`
+	Title        string `json:"title"`
+	Field        string `json:"field"`
+	Old          string `json:"old"`
+	New          string `json:"new"`
+}
+
+// RatingChange is one observed change of a film's personal rating
+// (field "rating") or Letterboxd average (field "letterboxd_rating")
+type RatingChange struct {
+	ID           int64     `json:"id"`
+	LetterboxdID string    `json:"letterboxd_id"`
+	Title        string    `json:"title"`
+	Field        string    `json:"field"`
+	OldValue     float64   `json:"old_value"`
+	NewValue     float64   `json:"new_value"`
+	ObservedAt   time.Time `json:"observed_at"`
 }
@@ -0,0 +1,226 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Sort keys accepted by MovieQuery.Sort
+const (
+	SortDateAdded        = "date_added"
+	SortTitle            = "title"
+	SortYear             = "year"
+	SortRating           = "rating"
+	SortLetterboxdRating = "letterboxd_rating"
+	SortLength           = "length"
+)
+
+// sortColumns maps sort keys to the SQL they order by
+var sortColumns = map[string]string{
+	SortDateAdded:        "date_added",
+	SortTitle:            "title COLLATE NOCASE",
+	SortYear:             "year",
+	SortRating:           "COALESCE(rating, 0)",
+	SortLetterboxdRating: "COALESCE(letterboxd_rating, 0)",
+	SortLength:           "COALESCE(length, 0)",
+}
+
+// movieColumns is the column list scanMovie expects
+const movieColumns = `letterboxd_id, title, year, letterboxd_url, rating, letterboxd_rating,
+		   length, date_added, poster_url, director, "cast", writers, letterboxd_uri,
+		   details_scraped_at, tmdb_id, tmdb_type, imdb_id, synopsis, liked`
+
+// PersonFilter matches movies crediting a person, in any role when Role is empty
+type PersonFilter struct {
+	Slug string `json:"slug"`
+	Role string `json:"role"`
+}
+
+// MovieQuery filters, sorts and pages movies. Zero values leave a filter
+// unset, so MovieQuery{} returns every movie, most recently added first.
+// Ranges are inclusive; dates are YYYY-MM-DD. Liked keeps only liked films.
+// Tags keeps films with a diary entry carrying each tag slug
+type MovieQuery struct {
+	Text                string         `json:"text"`
+	YearMin             int            `json:"year_min"`
+	YearMax             int            `json:"year_max"`
+	RatingMin           float64        `json:"rating_min"`
+	RatingMax           float64        `json:"rating_max"`
+	LetterboxdRatingMin float64        `json:"letterboxd_rating_min"`
+	LetterboxdRatingMax float64        `json:"letterboxd_rating_max"`
+	LengthMin           int            `json:"length_min"`
+	LengthMax           int            `json:"length_max"`
+	AddedFrom           string         `json:"added_from"`
+	AddedTo             string         `json:"added_to"`
+	Liked               bool           `json:"liked"`
+	People              []PersonFilter `json:"people"`
+	Genres              []string       `json:"genres"`
+	Tags                []string       `json:"tags"`
+	Sort                string         `json:"sort"`
+	Descending          bool           `json:"descending"`
+	Limit               int            `json:"limit"`
+	Offset              int            `json:"offset"`
+}
+
+// MovieQueryResult is one page of a MovieQuery and the number of movies
+// matching it across all pages
+type MovieQueryResult struct {
+	Movies []Movie `json:"movies"`
+	Total  int     `json:"total"`
+}
+
+// where compiles the filters to a WHERE clause and its parameters
+func (q MovieQuery) where() (string, []interface{}, error) {
+	var conds []string
+	var args []interface{}
+	add := func(cond string, values ...interface{}) {
+		conds = append(conds, cond)
+		args = append(args, values...)
+	}
+
+	if text := strings.TrimSpace(q.Text); text != "" {
+		add("title LIKE ?", "%"+text+"%")
+	}
+	if q.YearMin > 0 {
+		add("year >= ?", q.YearMin)
+	}
+	if q.YearMax > 0 {
+		add("year <= ?", q.YearMax)
+	}
+	if q.RatingMin > 0 {
+		add("rating >= ?", q.RatingMin)
+	}
+	if q.RatingMax > 0 {
+		add("rating <= ?", q.RatingMax)
+	}
+	if q.LetterboxdRatingMin > 0 {
+		add("letterboxd_rating >= ?", q.LetterboxdRatingMin)
+	}
+	if q.LetterboxdRatingMax > 0 {
+		add("letterboxd_rating <= ?", q.LetterboxdRatingMax)
+	}
+	if q.LengthMin > 0 {
+		add("length >= ?", q.LengthMin)
+	}
+	if q.LengthMax > 0 {
+		add("length > 0 AND length <= ?", q.LengthMax)
+	}
+
+	// date_added is RFC3339, so the date prefix compares correctly as text
+	if q.AddedFrom != "" {
+		if _, err := time.Parse(diaryDateLayout, q.AddedFrom); err != nil {
+			return "", nil, fmt.Errorf("invalid added_from date %q: %w", q.AddedFrom, err)
+		}
+		add("substr(date_added, 1, 10) >= ?", q.AddedFrom)
+	}
+	if q.AddedTo != "" {
+		if _, err := time.Parse(diaryDateLayout, q.AddedTo); err != nil {
+			return "", nil, fmt.Errorf("invalid added_to date %q: %w", q.AddedTo, err)
+		}
+		add("substr(date_added, 1, 10) <= ?", q.AddedTo)
+	}
+
+	if q.Liked {
+		add("liked = 1")
+	}
+
+	for _, person := range q.People {
+		if person.Role != "" {
+			add("EXISTS (SELECT 1 FROM credits c WHERE c.movie_id = letterboxd_id AND c.person_id = ? AND c.role = ?)", person.Slug, person.Role)
+		} else {
+			add("EXISTS (SELECT 1 FROM credits c WHERE c.movie_id = letterboxd_id AND c.person_id = ?)", person.Slug)
+		}
+	}
+	for _, genre := range q.Genres {
+		add("EXISTS (SELECT 1 FROM movie_genres g WHERE g.movie_id = letterboxd_id AND g.genre_id = ?)", genre)
+	}
+	for _, tag := range q.Tags {
+		add("EXISTS (SELECT 1 FROM viewings v JOIN viewing_tags vt ON vt.viewing_id = v.id WHERE v.movie_id = letterboxd_id AND vt.tag_id = ?)", tag)
+	}
+
+	if len(conds) == 0 {
+		return "", nil, nil
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args, nil
+}
+
+// orderBy compiles the sort key and direction to an ORDER BY clause.
+// letterboxd_id breaks ties so pages are stable
+func (q MovieQuery) orderBy() (string, error) {
+	key := q.Sort
+	descending := q.Descending
+	if key == "" {
+		key, descending = SortDateAdded, true
+	}
+
+	column, ok := sortColumns[key]
+	if !ok {
+		return "", fmt.Errorf("unknown sort key %q", q.Sort)
+	}
+
+	direction := "ASC"
+	if descending {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("ORDER BY %s %s, letterboxd_id %s", column, direction, direction), nil
+}
+
+// QueryMovies returns the page of movies matching q and the total match count
+func (m *MovieDB) QueryMovies(q MovieQuery) (MovieQueryResult, error) {
+	result := MovieQueryResult{Movies: []Movie{}}
+
+	where, args, err := q.where()
+	if err != nil {
+		return result, err
+	}
+	orderBy, err := q.orderBy()
+	if err != nil {
+		return result, err
+	}
+
+	if err := m.db.QueryRow("SELECT COUNT(*) FROM movies "+where, args...).Scan(&result.Total); err != nil {
+		return result, fmt.Errorf("failed to count movies: %w", err)
+	}
+
+	query := "SELECT " + movieColumns + "\n\tFROM movies\n\t" + where + "\n\t" + orderBy
+	if q.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, q.Limit, q.Offset)
+	} else if q.Offset > 0 {
+		query += " LIMIT -1 OFFSET ?"
+		args = append(args, q.Offset)
+	}
+
+	movies, err := m.selectMovies(query, args...)
+	if err != nil {
+		return result, err
+	}
+	result.Movies = movies
+
+	return result, nil
+}
+
+// selectMovies runs a SELECT of movieColumns and scans every row
+func (m *MovieDB) selectMovies(query string, args ...interface{}) ([]Movie, error) {
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query movies: %w", err)
+	}
+	defer rows.Close()
+
+	movies := []Movie{}
+	for rows.Next() {
+		movie, err := scanMovie(rows)
+		if err != nil {
+			return nil, err
+		}
+		movies = append(movies, movie)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return movies, nil
+}
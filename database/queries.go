@@ -3,275 +3,230 @@ package database
 import (
 	"database/sql"
 	"fmt"
-	"sort"
-	"strings"
 	"time"
 )
 
 // AddMovie inserts a new movie, existing movies are checked
-// so there would not be any conflicts
+// so there would not be any conflicts. Any credits on the movie
+// are written in the same transaction
 func (m *MovieDB) AddMovie(movie Movie) error {
 	query := `
 	INSERT INTO movies (
 		letterboxd_id, title, year, letterboxd_url, rating, letterboxd_rating,
-		length, date_added, poster_url, director, "cast", writers
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		length, date_added, poster_url, director, "cast", writers, letterboxd_uri,
+		details_scraped_at, tmdb_id, tmdb_type, imdb_id, synopsis, liked
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	stmt, err := m.db.Prepare(query)
+	tx, err := m.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer stmt.Close()
+	defer tx.Rollback()
 
-	now := time.Now().Format(time.RFC3339)
-	_, err = stmt.Exec(
+	// Keep the caller's date (e.g. from an export), otherwise stamp the import time
+	dateAdded := movie.DateAdded
+	if dateAdded.IsZero() {
+		dateAdded = time.Now()
+	}
+
+	_, err = tx.Exec(query,
 		movie.LetterboxdID, movie.Title, movie.Year, movie.LetterboxdURL,
-		movie.Rating, movie.LetterboxdRating, movie.Length, now, movie.PosterURL, movie.Director, movie.Cast, movie.Writers,
+		movie.Rating, movie.LetterboxdRating, movie.Length, dateAdded.Format(time.RFC3339), movie.PosterURL, movie.Director, movie.Cast, movie.Writers,
+		nullString(movie.LetterboxdURI), nullTime(movie.DetailsScrapedAt),
+		nullInt(movie.TmdbID), nullString(movie.TmdbType), nullString(movie.ImdbID),
+		nullString(movie.Synopsis), movie.Liked,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to execute insert: %w", err)
 	}
 
-	return nil
-}
-
-
-// GetAllMovies retrieves all movies from the database, ordered by date_added DESC
-func (m *MovieDB) GetAllMovies() ([]Movie, error) {
-	query := `
-	SELECT letterboxd_id, title, year, letterboxd_url, rating, letterboxd_rating,
-		   length, date_added, poster_url, director, "cast", writers
-	FROM movies
-	ORDER BY date_added DESC
-	`
-
-	rows, err := m.db.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query movies: %w", err)
+	if len(movie.Credits) > 0 {
+		if err := saveCredits(tx, movie.LetterboxdID, movie.Credits); err != nil {
+			return err
+		}
 	}
-	defer rows.Close()
 
-	var movies []Movie
-	for rows.Next() {
-		movie, err := scanMovie(rows)
-		if err != nil {
-			return nil, err
+	if hasTerms(movie) {
+		if err := saveTerms(tx, movie); err != nil {
+			return err
 		}
-		movies = append(movies, movie)
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
+	if err := reindexMovie(tx, movie.LetterboxdID); err != nil {
+		return err
 	}
 
-	fmt.Println(movies)
-	return movies, nil
-}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit movie: %w", err)
+	}
 
-// GetMoviesByRating retrieves movies with a rating >= minRating
-func (m *MovieDB) GetMoviesByRating(minRating float64) ([]Movie, error) {
-	query := `
-	SELECT letterboxd_id, title, year, letterboxd_url, rating, letterboxd_rating,
-		   length, date_added, poster_url, director, "cast", writers
-	FROM movies
-	WHERE rating >= ?
-	ORDER BY rating DESC
-	`
+	return nil
+}
 
-	rows, err := m.db.Query(query, minRating)
+// UpsertMovie inserts a movie or updates the stored row, returning the
+// fields that changed. date_added and letterboxd_uri of an existing row are
+// kept, as are scraped details the movie leaves empty, and credits and classifications (genres, countries, ...) are only
+// replaced when the movie carries some.
+// Rating changes are also written to rating_history
+func (m *MovieDB) UpsertMovie(movie Movie) ([]FieldChange, error) {
+	existing, found, err := m.GetMovie(movie.LetterboxdID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query movies by rating: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
-
-	var movies []Movie
-	for rows.Next() {
-		movie, err := scanMovie(rows)
-		if err != nil {
-			return nil, err
-		}
-		movies = append(movies, movie)
+	if !found {
+		return nil, m.AddMovie(movie)
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
+	changes := diffMovies(existing, movie)
+	if len(changes) == 0 && len(movie.Credits) == 0 && !hasTerms(movie) && movie.DetailsScrapedAt.IsZero() {
+		return nil, nil
 	}
 
-	return movies, nil
-}
-
-// SearchByTitle searches for movies by title (case-insensitive)
-func (m *MovieDB) SearchByTitle(title string) ([]Movie, error) {
 	query := `
-	SELECT letterboxd_id, title, year, letterboxd_url, rating, letterboxd_rating,
-		   length, date_added, poster_url, director, "cast", writers
-	FROM movies
-	WHERE title LIKE ?
-	ORDER BY title ASC
+	UPDATE movies SET
+		title = ?, year = ?, letterboxd_url = ?, rating = ?,
+		letterboxd_rating = COALESCE(NULLIF(?, 0), letterboxd_rating),
+		length = COALESCE(NULLIF(?, 0), length),
+		poster_url = COALESCE(NULLIF(?, ''), poster_url),
+		director = COALESCE(NULLIF(?, ''), director),
+		"cast" = COALESCE(NULLIF(?, ''), "cast"),
+		writers = COALESCE(NULLIF(?, ''), writers),
+		liked = ?,
+		details_scraped_at = COALESCE(?, details_scraped_at),
+		tmdb_id = COALESCE(?, tmdb_id), tmdb_type = COALESCE(?, tmdb_type),
+		imdb_id = COALESCE(?, imdb_id), synopsis = COALESCE(?, synopsis)
+	WHERE letterboxd_id = ?
 	`
 
-	rows, err := m.db.Query(query, "%"+title+"%")
+	tx, err := m.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to search movies: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer rows.Close()
+	defer tx.Rollback()
 
-	var movies []Movie
-	for rows.Next() {
-		movie, err := scanMovie(rows)
-		if err != nil {
-			return nil, err
-		}
-		movies = append(movies, movie)
+	_, err = tx.Exec(query,
+		movie.Title, movie.Year, movie.LetterboxdURL, movie.Rating, movie.LetterboxdRating,
+		movie.Length, movie.PosterURL, movie.Director, movie.Cast, movie.Writers, movie.Liked,
+		nullTime(movie.DetailsScrapedAt),
+		nullInt(movie.TmdbID), nullString(movie.TmdbType), nullString(movie.ImdbID),
+		nullString(movie.Synopsis), movie.LetterboxdID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute update: %w", err)
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
+	if err := saveRatingHistory(tx, existing, movie, time.Now()); err != nil {
+		return nil, err
 	}
 
-	return movies, nil
-}
-
-// GetMoviesByYear retrieves all movies from a specific year
-func (m *MovieDB) GetMoviesByYear(year int) ([]Movie, error) {
-	query := `
-	SELECT letterboxd_id, title, year, letterboxd_url, rating, letterboxd_rating,
-		   length, date_added, poster_url, director, "cast", writers
-	FROM movies
-	WHERE year = ?
-	ORDER BY date_added DESC
-	`
-
-	rows, err := m.db.Query(query, year)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query movies by year: %w", err)
+	if len(movie.Credits) > 0 {
+		if err := saveCredits(tx, movie.LetterboxdID, movie.Credits); err != nil {
+			return nil, err
+		}
 	}
-	defer rows.Close()
 
-	var movies []Movie
-	for rows.Next() {
-		movie, err := scanMovie(rows)
-		if err != nil {
+	if hasTerms(movie) {
+		if err := saveTerms(tx, movie); err != nil {
 			return nil, err
 		}
-		movies = append(movies, movie)
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
+	if err := reindexMovie(tx, movie.LetterboxdID); err != nil {
+		return nil, err
 	}
 
-	return movies, nil
-}
-
-// GetStats calculates various statistics about the movie collection
-func (m *MovieDB) GetStats() (map[string]interface{}, error) {
-	stats := make(map[string]interface{})
-
-	// Total movies watched
-	var totalMovies int
-	err := m.db.QueryRow("SELECT COUNT(*) FROM movies").Scan(&totalMovies)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get total movies: %w", err)
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit movie: %w", err)
 	}
-	stats["total_movies"] = totalMovies
 
-	// Average personal rating
-	var avgRating sql.NullFloat64
-	err = m.db.QueryRow("SELECT AVG(rating) FROM movies WHERE rating > 0").Scan(&avgRating)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get average rating: %w", err)
-	}
-	if avgRating.Valid {
-		stats["average_rating"] = avgRating.Float64
-	} else {
-		stats["average_rating"] = 0.0
-	}
+	return changes, nil
+}
 
-	// Average letterboxd rating
-	var avgLetterboxdRating sql.NullFloat64
-	err = m.db.QueryRow("SELECT AVG(letterboxd_rating) FROM movies").Scan(&avgLetterboxdRating)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get average letterboxd rating: %w", err)
-	}
-	if avgLetterboxdRating.Valid {
-		stats["average_letterboxd_rating"] = avgLetterboxdRating.Float64
-	} else {
-		stats["average_letterboxd_rating"] = 0.0
+// diffMovies lists the user-visible fields that differ between two versions
+// of a movie. Scraped details left empty in updated are kept by UpsertMovie
+// and not reported
+func diffMovies(old, updated Movie) []FieldChange {
+	var changes []FieldChange
+	add := func(field string, before, after interface{}) {
+		b, a := fmt.Sprint(before), fmt.Sprint(after)
+		if b != a {
+			changes = append(changes, FieldChange{
+				LetterboxdID: updated.LetterboxdID,
+				Title:        updated.Title,
+				Field:        field,
+				Old:          b,
+				New:          a,
+			})
+		}
 	}
 
-	// Total runtime in minutes
-	var totalMinutes sql.NullInt64
-	err = m.db.QueryRow("SELECT COALESCE(SUM(length), 0) FROM movies").Scan(&totalMinutes)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get total runtime: %w", err)
+	add("title", old.Title, updated.Title)
+	add("year", old.Year, updated.Year)
+	add("rating", old.Rating, updated.Rating)
+	addScraped := func(field string, before, after interface{}) {
+		if a := fmt.Sprint(after); a != "0" && a != "" {
+			add(field, before, after)
+		}
 	}
+	addScraped("letterboxd_rating", old.LetterboxdRating, updated.LetterboxdRating)
+	addScraped("length", old.Length, updated.Length)
+	addScraped("poster_url", old.PosterURL, updated.PosterURL)
+	addScraped("director", old.Director, updated.Director)
+	addScraped("cast", old.Cast, updated.Cast)
+	addScraped("writers", old.Writers, updated.Writers)
+	add("liked", old.Liked, updated.Liked)
+
+	return changes
+}
 
-	runtimeMinutes := totalMinutes.Int64
-	days := runtimeMinutes / (24 * 60)
-	hours := (runtimeMinutes % (24 * 60)) / 60
-	minutes := runtimeMinutes % 60
+// GetMovie retrieves a single movie by letterboxd_id.
+// found is false if no such movie exists
+func (m *MovieDB) GetMovie(letterboxdID string) (movie Movie, found bool, err error) {
+	return m.getMovieWhere("letterboxd_id = ?", letterboxdID)
+}
 
-	stats["total_runtime_minutes"] = runtimeMinutes
-	stats["total_runtime_formatted"] = fmt.Sprintf("%d days, %d hours, %d minutes", days, hours, minutes)
+// GetMovieByTmdbID retrieves a movie by its TMDB id. tmdbType is "movie"
+// or "tv", as TMDB numbers films and shows separately
+func (m *MovieDB) GetMovieByTmdbID(tmdbType string, tmdbID int) (movie Movie, found bool, err error) {
+	return m.getMovieWhere("tmdb_type = ? AND tmdb_id = ?", tmdbType, tmdbID)
+}
 
-	// Average runtime in minutes
-	averageRuntimeMinutes := runtimeMinutes / int64(totalMovies)
-	hours = averageRuntimeMinutes / 60
-	minutes = averageRuntimeMinutes % 60
+// GetMovieByImdbID retrieves a movie by its IMDb id, e.g. "tt0211915"
+func (m *MovieDB) GetMovieByImdbID(imdbID string) (movie Movie, found bool, err error) {
+	return m.getMovieWhere("imdb_id = ?", imdbID)
+}
 
-	stats["average_runtime_minutes"] = averageRuntimeMinutes
-	stats["average_runtime_formatted"] = fmt.Sprintf("%d hours, %d minutes", hours, minutes)
+// getMovieWhere retrieves the first movie matching a WHERE condition
+func (m *MovieDB) getMovieWhere(condition string, args ...interface{}) (movie Movie, found bool, err error) {
+	query := `
+	SELECT ` + movieColumns + `
+	FROM movies
+	WHERE ` + condition + `
+	LIMIT 1
+	`
 
-	// Movies by year (top 10 years)
-	rows, err := m.db.Query(`
-		SELECT year, COUNT(*) as count FROM movies 
-		WHERE year > 0 
-		GROUP BY year 
-		ORDER BY count DESC 
-		LIMIT 10
-	`)
+	rows, err := m.db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get movies by year: %w", err)
+		return movie, false, fmt.Errorf("failed to query movie: %w", err)
 	}
 	defer rows.Close()
 
-	moviesByYear := make(map[int]int)
-	for rows.Next() {
-		var year, count int
-		if err := rows.Scan(&year, &count); err != nil {
-			return nil, fmt.Errorf("failed to scan year stats: %w", err)
-		}
-		moviesByYear[year] = count
+	if !rows.Next() {
+		return movie, false, rows.Err()
 	}
-	stats["movies_by_year"] = moviesByYear
 
-	// Top rated movies
-	topMovies, err := m.GetMoviesByRating(0)
-	if err == nil && len(topMovies) > 10 {
-		stats["top_movies"] = topMovies[:10]
-	} else {
-		stats["top_movies"] = topMovies
+	movie, err = scanMovie(rows)
+	if err != nil {
+		return movie, false, err
 	}
 
-	// Top directors
-	topDirectors := m.getTopPeople("director", 10)
-	stats["top_directors"] = topDirectors
-
-	// Top actors
-	topActors := m.getTopPeople("cast", 10)
-	stats["top_actors"] = topActors
-
-	// Top writers
-	topWriters := m.getTopPeople("writers", 10)
-	stats["top_writers"] = topWriters
-
-	return stats, nil
+	return movie, true, nil
 }
 
-// scanMovie scans a single movie from database rows to Movie struct
-func scanMovie(rows *sql.Rows) (Movie, error) {
+// scanMovie scans a single movie from database rows to Movie struct.
+// Columns selected after the movie's own are scanned into extra
+func scanMovie(rows *sql.Rows, extra ...interface{}) (Movie, error) {
 	var movie Movie
 	var rating sql.NullFloat64
 	var letterboxdRating sql.NullFloat64
@@ -280,9 +235,15 @@ func scanMovie(rows *sql.Rows) (Movie, error) {
 	var director sql.NullString
 	var cast sql.NullString
 	var writers sql.NullString
+	var uri sql.NullString
 	var dateAddedStr sql.NullString
+	var detailsScrapedAt sql.NullString
+	var tmdbID sql.NullInt64
+	var tmdbType sql.NullString
+	var imdbID sql.NullString
+	var synopsis sql.NullString
 
-	err := rows.Scan(
+	dest := []interface{}{
 		&movie.LetterboxdID,
 		&movie.Title,
 		&movie.Year,
@@ -295,17 +256,27 @@ func scanMovie(rows *sql.Rows) (Movie, error) {
 		&director,
 		&cast,
 		&writers,
-	)
+		&uri,
+		&detailsScrapedAt,
+		&tmdbID,
+		&tmdbType,
+		&imdbID,
+		&synopsis,
+		&movie.Liked,
+	}
+
+	err := rows.Scan(append(dest, extra...)...)
 	if err != nil {
 		return movie, fmt.Errorf("failed to scan movie row: %w", err)
 	}
 
-	// Parse date
+	// Parse date, written as RFC3339 by AddMovie (older rows may use the SQLite format)
 	if dateAddedStr.Valid {
-		parsed, err := time.Parse("2006-01-02 15:04:05", dateAddedStr.String)
-		if err == nil {
+		if parsed, err := time.Parse(time.RFC3339, dateAddedStr.String); err == nil {
 			movie.DateAdded = parsed
-		} 
+		} else if parsed, err := time.Parse("2006-01-02 15:04:05", dateAddedStr.String); err == nil {
+			movie.DateAdded = parsed
+		}
 	}
 
 	// Handle other NULL values
@@ -330,10 +301,36 @@ func scanMovie(rows *sql.Rows) (Movie, error) {
 	if writers.Valid {
 		movie.Writers = writers.String
 	}
+	movie.LetterboxdURI = uri.String
+	if parsed, err := time.Parse(time.RFC3339, detailsScrapedAt.String); err == nil {
+		movie.DetailsScrapedAt = parsed
+	}
+	movie.TmdbID = int(tmdbID.Int64)
+	movie.TmdbType = tmdbType.String
+	movie.ImdbID = imdbID.String
+	movie.Synopsis = synopsis.String
 
 	return movie, nil
 }
 
+// nullString maps an empty string to NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullTime maps a zero time to NULL, otherwise formats it as RFC3339
+func nullTime(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: t.Format(time.RFC3339), Valid: true}
+}
+
+// nullInt maps zero to NULL
+func nullInt(i int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(i), Valid: i != 0}
+}
+
 // GetStats calculates and returns total number of movies for easy access
 func (m *MovieDB) GetMovieCount() (int, error) {
 	var count int
@@ -343,79 +340,3 @@ func (m *MovieDB) GetMovieCount() (int, error) {
 	}
 	return count, nil
 }
-
-// getTopPeople returns top N people (directors, actors, or writers)
-// by counting comma-separated values inside the column.
-// field must be one of: "director", "cast", "writers"
-func (m *MovieDB) getTopPeople(field string, limit int) []map[string]interface{} {
-    results := []map[string]interface{}{}
-
-    validFields := map[string]string{
-        "director": "director",
-        "cast":     `"cast"`,
-        "writers":  "writers",
-    }
-
-    column, ok := validFields[field]
-    if !ok {
-        fmt.Printf("invalid field name: %s\n", field)
-        return results
-    }
-
-    // Query raw strings, let Go do the splitting
-    query := fmt.Sprintf(`SELECT %s FROM movies WHERE %s IS NOT NULL AND %s != ''`,
-        column, column, column)
-
-    rows, err := m.db.Query(query)
-    if err != nil {
-        fmt.Printf("failed to query %s: %v\n", field, err)
-        return results
-    }
-    defer rows.Close()
-
-    // Count map
-    counts := make(map[string]int)
-
-    for rows.Next() {
-        var raw string
-        if err := rows.Scan(&raw); err != nil {
-            continue
-        }
-
-        // Split by comma
-        parts := strings.Split(raw, ",")
-        for _, p := range parts {
-            name := strings.TrimSpace(p)
-            if name == "" {
-                continue
-            }
-            counts[name]++
-        }
-    }
-
-    // Convert map -> slice
-    type person struct {
-        Name  string
-        Count int
-    }
-
-    list := make([]person, 0, len(counts))
-    for name, c := range counts {
-        list = append(list, person{Name: name, Count: c})
-    }
-
-    // Sort descending by count
-    sort.Slice(list, func(i, j int) bool {
-        return list[i].Count > list[j].Count
-    })
-
-    // Limit results
-    for i := 0; i < len(list) && i < limit; i++ {
-        results = append(results, map[string]interface{}{
-            "name":        list[i].Name,
-            "movie_count": list[i].Count,
-        })
-    }
-
-    return results
-}
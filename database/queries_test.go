@@ -0,0 +1,72 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestUpsertMovieKeepsScrapedDetails(t *testing.T) {
+	db := newTestDB(t)
+
+	scraped := Movie{
+		LetterboxdID:     "/film/paterson",
+		Title:            "Paterson",
+		Year:             2016,
+		LetterboxdURL:    "/film/paterson/",
+		Rating:           4,
+		LetterboxdRating: 3.98,
+		Length:           118,
+		PosterURL:        "https://a.ltrbxd.com/paterson.jpg",
+		Director:         "Jim Jarmusch",
+		Cast:             "Adam Driver",
+		Writers:          "Jim Jarmusch",
+	}
+	if err := db.AddMovie(scraped); err != nil {
+		t.Fatalf("AddMovie: %v", err)
+	}
+
+	// A films page only carries the title, year, rating and like
+	listed := Movie{
+		LetterboxdID:  "/film/paterson",
+		Title:         "Paterson",
+		Year:          2016,
+		LetterboxdURL: "/film/paterson/",
+		Rating:        4.5,
+		Liked:         true,
+	}
+	changes, err := db.UpsertMovie(listed)
+	if err != nil {
+		t.Fatalf("UpsertMovie: %v", err)
+	}
+	var fields []string
+	for _, change := range changes {
+		fields = append(fields, change.Field)
+	}
+	if len(fields) != 2 || fields[0] != "rating" || fields[1] != "liked" {
+		t.Errorf("changed fields = %v, want [rating liked]", fields)
+	}
+
+	movie, _, err := db.GetMovie("/film/paterson")
+	if err != nil {
+		t.Fatalf("GetMovie: %v", err)
+	}
+	if movie.Rating != 4.5 || !movie.Liked {
+		t.Errorf("rating %v, liked %v, want 4.5, true", movie.Rating, movie.Liked)
+	}
+	if movie.LetterboxdRating != scraped.LetterboxdRating || movie.Length != scraped.Length ||
+		movie.PosterURL != scraped.PosterURL || movie.Director != scraped.Director ||
+		movie.Cast != scraped.Cast || movie.Writers != scraped.Writers {
+		t.Errorf("scraped details lost: %+v", movie)
+	}
+
+	// Clearing the rating and like is still stored
+	if _, err := db.UpsertMovie(Movie{LetterboxdID: "/film/paterson", Title: "Paterson", Year: 2016, LetterboxdURL: "/film/paterson/"}); err != nil {
+		t.Fatalf("UpsertMovie: %v", err)
+	}
+	movie, _, _ = db.GetMovie("/film/paterson")
+	if movie.Rating != 0 || movie.Liked {
+		t.Errorf("rating %v, liked %v, want 0, false", movie.Rating, movie.Liked)
+	}
+	if movie.Director != scraped.Director {
+		t.Errorf("director = %q, want %q", movie.Director, scraped.Director)
+	}
+}
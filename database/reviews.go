@@ -0,0 +1,147 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Review is one of the user's reviews. WatchedDate is zero for reviews not
+// logged on a date and Rating is zero when the film was not rated with it.
+// Title is filled in from movies when reading
+type Review struct {
+	Key          string    `json:"key"`
+	LetterboxdID string    `json:"letterboxd_id"`
+	Title        string    `json:"title"`
+	URL          string    `json:"url"`
+	WatchedDate  time.Time `json:"watched_date"`
+	Rating       float64   `json:"rating"`
+	Text         string    `json:"text"`
+	LikeCount    int       `json:"like_count"`
+	Spoiler      bool      `json:"spoiler"`
+}
+
+// ReviewKey builds the identifier used to deduplicate reviews: the
+// ViewingKey of the diary entry, or "<letterboxd_id>:undated" when it has
+// no date. The export and the site link a review differently, so the film
+// is the only part both share and a film keeps one undated review
+func ReviewKey(letterboxdID string, watched time.Time) string {
+	if watched.IsZero() {
+		return letterboxdID + ":undated"
+	}
+	return ViewingKey(letterboxdID, watched)
+}
+
+// SaveReview inserts a review, or updates it if a review with the same key
+// was already saved, and reindexes the film for full-text search
+func (m *MovieDB) SaveReview(review Review) error {
+	if review.Key == "" {
+		review.Key = ReviewKey(review.LetterboxdID, review.WatchedDate)
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var watched sql.NullString
+	if !review.WatchedDate.IsZero() {
+		watched = sql.NullString{String: review.WatchedDate.Format(diaryDateLayout), Valid: true}
+	}
+	var rating sql.NullFloat64
+	if review.Rating > 0 {
+		rating = sql.NullFloat64{Float64: review.Rating, Valid: true}
+	}
+
+	_, err = tx.Exec(`
+	INSERT INTO reviews (review_key, movie_id, url, watched_date, rating, body, like_count, spoiler)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(review_key) DO UPDATE SET
+		url = COALESCE(excluded.url, reviews.url),
+		rating = excluded.rating,
+		body = excluded.body,
+		like_count = excluded.like_count,
+		spoiler = excluded.spoiler
+	`, review.Key, review.LetterboxdID, nullString(review.URL), watched, rating, review.Text,
+		review.LikeCount, review.Spoiler)
+	if err != nil {
+		return fmt.Errorf("failed to save review: %w", err)
+	}
+
+	if err := reindexMovie(tx, review.LetterboxdID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit review: %w", err)
+	}
+
+	return nil
+}
+
+// ReviewExists checks if a review with the given key was already saved
+func (m *MovieDB) ReviewExists(reviewKey string) (bool, error) {
+	var count int
+
+	query := "SELECT COUNT(*) FROM reviews WHERE review_key = ?"
+	if err := m.db.QueryRow(query, reviewKey).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check if review exists: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// GetReviews retrieves every review of a movie, most recent first
+func (m *MovieDB) GetReviews(letterboxdID string) ([]Review, error) {
+	return m.queryReviews("WHERE r.movie_id = ?", letterboxdID)
+}
+
+// GetReviewsBetween retrieves the reviews of viewings watched within
+// [from, to], most recent first. Undated reviews are not included
+func (m *MovieDB) GetReviewsBetween(from, to time.Time) ([]Review, error) {
+	return m.queryReviews("WHERE r.watched_date BETWEEN ? AND ?",
+		from.Format(diaryDateLayout), to.Format(diaryDateLayout))
+}
+
+// queryReviews selects reviews matching a WHERE clause and scans every row
+func (m *MovieDB) queryReviews(where string, args ...interface{}) ([]Review, error) {
+	rows, err := m.db.Query(`
+	SELECT r.review_key, r.movie_id, m.title, r.url, r.watched_date, r.rating, r.body, r.like_count, r.spoiler
+	FROM reviews r
+	JOIN movies m ON m.letterboxd_id = r.movie_id
+	`+where+`
+	ORDER BY r.watched_date DESC, r.review_key DESC
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []Review{}
+	for rows.Next() {
+		var review Review
+		var url, watched sql.NullString
+		var rating sql.NullFloat64
+
+		err := rows.Scan(&review.Key, &review.LetterboxdID, &review.Title, &url, &watched, &rating,
+			&review.Text, &review.LikeCount, &review.Spoiler)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review row: %w", err)
+		}
+
+		review.URL = url.String
+		if parsed, err := time.Parse(diaryDateLayout, watched.String); err == nil {
+			review.WatchedDate = parsed
+		}
+		review.Rating = rating.Float64
+
+		reviews = append(reviews, review)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return reviews, nil
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Markers SQLite puts around matched terms; they cannot occur in page text,
+// so the result can be HTML-escaped before they become <mark> tags
+const (
+	matchStart = "\x02"
+	matchEnd   = "\x03"
+)
+
+// SearchResult is a movie matched by Search. TitleHTML and Snippet are
+// HTML-escaped with the matched terms wrapped in <mark> tags
+type SearchResult struct {
+	Movie     Movie   `json:"movie"`
+	TitleHTML string  `json:"title_html"`
+	Snippet   string  `json:"snippet"`
+	Score     float64 `json:"score"`
+}
+
+// reindexMovie rewrites the full-text search row of a movie from its
+// current title, credits, synopsis and reviews inside the given transaction
+func reindexMovie(tx *sql.Tx, movieID string) error {
+	if _, err := tx.Exec("DELETE FROM movie_search WHERE letterboxd_id = ?", movieID); err != nil {
+		return fmt.Errorf("failed to clear search index: %w", err)
+	}
+
+	query := `
+	INSERT INTO movie_search (letterboxd_id, title, people, synopsis, reviews)
+	SELECT m.letterboxd_id, m.title,
+		COALESCE((
+			SELECT group_concat(p.name, ' ')
+			FROM credits c
+			JOIN people p ON p.slug = c.person_id
+			WHERE c.movie_id = m.letterboxd_id
+		), ''),
+		COALESCE(m.synopsis, ''),
+		COALESCE((
+			SELECT group_concat(r.body, ' ')
+			FROM reviews r
+			WHERE r.movie_id = m.letterboxd_id
+		), '')
+	FROM movies m
+	WHERE m.letterboxd_id = ?
+	`
+
+	if _, err := tx.Exec(query, movieID); err != nil {
+		return fmt.Errorf("failed to index movie: %w", err)
+	}
+
+	return nil
+}
+
+// Search runs a full-text search over titles, people, synopses and reviews,
+// best matches first. Every word of the query must match, as a prefix,
+// ignoring case and diacritics
+func (m *MovieDB) Search(text string, limit int) ([]SearchResult, error) {
+	match := ftsQuery(text)
+	if match == "" {
+		return []SearchResult{}, nil
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	// bm25 weights: title matches count most, then people, then the rest
+	query := `
+	SELECT m.letterboxd_id, m.title, m.year, m.letterboxd_url, m.rating, m.letterboxd_rating,
+		   m.length, m.date_added, m.poster_url, m.director, m."cast", m.writers, m.letterboxd_uri,
+		   m.details_scraped_at, m.tmdb_id, m.tmdb_type, m.imdb_id, m.synopsis, m.liked,
+		   highlight(movie_search, 1, ?, ?),
+		   snippet(movie_search, -1, ?, ?, '…', 16),
+		   bm25(movie_search, 0.0, 10.0, 5.0, 1.0, 1.0) AS score
+	FROM movie_search s
+	JOIN movies m ON m.letterboxd_id = s.letterboxd_id
+	WHERE movie_search MATCH ?
+	ORDER BY score ASC
+	LIMIT ?
+	`
+
+	rows, err := m.db.Query(query, matchStart, matchEnd, matchStart, matchEnd, match, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search movies: %w", err)
+	}
+	defer rows.Close()
+
+	results := []SearchResult{}
+	for rows.Next() {
+		var result SearchResult
+		var title, snippet string
+		movie, err := scanMovie(rows, &title, &snippet, &result.Score)
+		if err != nil {
+			return nil, err
+		}
+		result.Movie = movie
+		result.TitleHTML = markMatches(title)
+		result.Snippet = markMatches(snippet)
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// ftsQuery turns free text into an FTS5 query matching every word as a
+// prefix, quoting each word so punctuation is never read as query syntax
+// From: `amelie jeun` -> `"amelie"* "jeun"*`
+func ftsQuery(text string) string {
+	var terms []string
+	for _, word := range strings.Fields(text) {
+		word = strings.ReplaceAll(word, `"`, "")
+		if word == "" {
+			continue
+		}
+		terms = append(terms, `"`+word+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// markMatches escapes text for HTML and turns the match markers into <mark> tags
+func markMatches(text string) string {
+	text = html.EscapeString(text)
+	text = strings.ReplaceAll(text, matchStart, "<mark>")
+	return strings.ReplaceAll(text, matchEnd, "</mark>")
+}
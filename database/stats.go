@@ -0,0 +1,278 @@
+package database
+
+import "fmt"
+
+// Stats summarises the movies in a StatsFilter's scope
+type Stats struct {
+	TotalMovies             int          `json:"total_movies"`
+	AverageRating           float64      `json:"average_rating"`
+	AverageLetterboxdRating float64      `json:"average_letterboxd_rating"`
+	TotalRuntimeMinutes     int          `json:"total_runtime_minutes"`
+	TotalRuntimeFormatted   string       `json:"total_runtime_formatted"`
+	AverageRuntimeMinutes   int          `json:"average_runtime_minutes"`
+	AverageRuntimeFormatted string       `json:"average_runtime_formatted"`
+	TotalViewings           int          `json:"total_viewings"`
+	TotalRewatches          int          `json:"total_rewatches"`
+	TotalLiked              int          `json:"total_liked"`
+	ViewingsByYear          []YearCount  `json:"viewings_by_year"`
+	MoviesByYear            []YearCount  `json:"movies_by_year"`
+	TopMovies               []Movie      `json:"top_movies"`
+	TopDirectors            []PersonStat `json:"top_directors"`
+	TopActors               []PersonStat `json:"top_actors"`
+	TopWriters              []PersonStat `json:"top_writers"`
+	TopGenres               []TermStat   `json:"top_genres"`
+	TopCountries            []TermStat   `json:"top_countries"`
+	TopLanguages            []TermStat   `json:"top_languages"`
+
+	ReleaseYears                 []TimelinePoint    `json:"release_years"`
+	ReleaseDecades               []TimelinePoint    `json:"release_decades"`
+	RatingDistribution           RatingDistribution `json:"rating_distribution"`
+	LetterboxdRatingDistribution RatingDistribution `json:"letterboxd_rating_distribution"`
+}
+
+// YearCount is the number of movies or viewings in one year
+type YearCount struct {
+	Year  int `json:"year"`
+	Count int `json:"count"`
+}
+
+// PersonStat is a person and the number of movies they are credited on
+type PersonStat struct {
+	Slug       string `json:"slug"`
+	Name       string `json:"name"`
+	MovieCount int    `json:"movie_count"`
+}
+
+// TermStat is a genre, country or language and its number of movies
+type TermStat struct {
+	Slug       string `json:"slug"`
+	Name       string `json:"name"`
+	MovieCount int    `json:"movie_count"`
+}
+
+// StatsFilter limits stats to a subset of the collection.
+// Zero fields are unset, so StatsFilter{} covers every movie.
+// Decade is its first year, e.g. 1970, and is ignored when Year is set.
+// Tag keeps films with a diary entry carrying that tag slug, and only
+// those entries count as viewings
+type StatsFilter struct {
+	Year      int     `json:"year"`
+	Decade    int     `json:"decade"`
+	RatingMin float64 `json:"rating_min"`
+	RatingMax float64 `json:"rating_max"`
+	Genre     string  `json:"genre"`
+	Liked     bool    `json:"liked"`
+	Tag       string  `json:"tag"`
+}
+
+// movieQuery expresses the filter as a MovieQuery
+func (f StatsFilter) movieQuery() MovieQuery {
+	q := MovieQuery{
+		YearMin:   f.Year,
+		YearMax:   f.Year,
+		RatingMin: f.RatingMin,
+		RatingMax: f.RatingMax,
+		Liked:     f.Liked,
+	}
+	if f.Year == 0 && f.Decade > 0 {
+		q.YearMin, q.YearMax = f.Decade, f.Decade+9
+	}
+	if f.Genre != "" {
+		q.Genres = []string{f.Genre}
+	}
+	if f.Tag != "" {
+		q.Tags = []string{f.Tag}
+	}
+	return q
+}
+
+// viewingScope selects the viewings counted for the movies in scope:
+// all of them, or only the entries carrying the filter's tag
+func (f StatsFilter) viewingScope(inScope string, args []interface{}) (string, []interface{}) {
+	scope := "movie_id IN (" + inScope + ")"
+	if f.Tag == "" {
+		return scope, args
+	}
+	scope += " AND id IN (SELECT viewing_id FROM viewing_tags WHERE tag_id = ?)"
+	return scope, append(append([]interface{}{}, args...), f.Tag)
+}
+
+// GetStats calculates statistics over the movies matching filter.
+// A zero filter covers the whole collection
+func (m *MovieDB) GetStats(filter StatsFilter) (Stats, error) {
+	stats := Stats{
+		ViewingsByYear: []YearCount{},
+		MoviesByYear:   []YearCount{},
+		TopMovies:      []Movie{},
+		ReleaseYears:   []TimelinePoint{},
+		ReleaseDecades: []TimelinePoint{},
+	}
+
+	query := filter.movieQuery()
+	where, args, err := query.where()
+	if err != nil {
+		return stats, err
+	}
+	// Subquery selecting the ids of the movies in scope
+	inScope := "SELECT letterboxd_id FROM movies " + where
+
+	// Total movies, likes, runtime and averages. Averages skip films without
+	// a rating or runtime, and AVG is NULL without rows, hence COALESCE
+	err = m.db.QueryRow(`
+		SELECT COUNT(*),
+			COALESCE(SUM(liked), 0),
+			COALESCE(SUM(length), 0),
+			COALESCE(CAST(AVG(CASE WHEN length > 0 THEN length END) AS INTEGER), 0),
+			COALESCE(AVG(CASE WHEN rating > 0 THEN rating END), 0),
+			COALESCE(AVG(CASE WHEN letterboxd_rating > 0 THEN letterboxd_rating END), 0)
+		FROM movies `+where, args...).Scan(
+		&stats.TotalMovies, &stats.TotalLiked, &stats.TotalRuntimeMinutes, &stats.AverageRuntimeMinutes,
+		&stats.AverageRating, &stats.AverageLetterboxdRating,
+	)
+	if err != nil {
+		return stats, fmt.Errorf("failed to get movie totals: %w", err)
+	}
+
+	runtimeMinutes := stats.TotalRuntimeMinutes
+	stats.TotalRuntimeFormatted = fmt.Sprintf("%d days, %d hours, %d minutes",
+		runtimeMinutes/(24*60), (runtimeMinutes%(24*60))/60, runtimeMinutes%60)
+
+	stats.AverageRuntimeFormatted = fmt.Sprintf("%d hours, %d minutes",
+		stats.AverageRuntimeMinutes/60, stats.AverageRuntimeMinutes%60)
+
+	stats.RatingDistribution, stats.LetterboxdRatingDistribution, err = m.getRatingDistributions(where, args)
+	if err != nil {
+		return stats, err
+	}
+
+	// Diary viewings, counted by the date they were actually watched
+	viewings, viewingArgs := filter.viewingScope(inScope, args)
+	err = m.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(rewatch), 0)
+		FROM viewings
+		WHERE `+viewings, viewingArgs...).Scan(&stats.TotalViewings, &stats.TotalRewatches)
+	if err != nil {
+		return stats, fmt.Errorf("failed to get viewing counts: %w", err)
+	}
+
+	stats.ViewingsByYear, err = m.countByYear(`
+		SELECT CAST(substr(watched_date, 1, 4) AS INTEGER) AS watched_year, COUNT(*)
+		FROM viewings
+		WHERE `+viewings+`
+		GROUP BY watched_year
+		ORDER BY watched_year ASC
+	`, viewingArgs...)
+	if err != nil {
+		return stats, fmt.Errorf("failed to get viewings by year: %w", err)
+	}
+
+	// Movies by year (top 10 years)
+	stats.MoviesByYear, err = m.countByYear(`
+		SELECT year, COUNT(*) AS count
+		FROM movies
+		WHERE year > 0 AND letterboxd_id IN (`+inScope+`)
+		GROUP BY year
+		ORDER BY count DESC, year DESC
+		LIMIT 10
+	`, args...)
+	if err != nil {
+		return stats, fmt.Errorf("failed to get movies by year: %w", err)
+	}
+
+	// Complete release timelines, by year and by decade
+	if stats.ReleaseYears, err = m.getTimeline(1, inScope, args); err != nil {
+		return stats, err
+	}
+	if stats.ReleaseDecades, err = m.getTimeline(10, inScope, args); err != nil {
+		return stats, err
+	}
+
+	// Top rated movies
+	query.Sort, query.Descending, query.Limit = SortRating, true, 10
+	topMovies, err := m.QueryMovies(query)
+	if err != nil {
+		return stats, err
+	}
+	stats.TopMovies = topMovies.Movies
+
+	// Top directors, actors and writers
+	if stats.TopDirectors, err = m.getTopPeople(RoleDirector, 10, inScope, args); err != nil {
+		return stats, err
+	}
+	if stats.TopActors, err = m.getTopPeople(RoleActor, 10, inScope, args); err != nil {
+		return stats, err
+	}
+	if stats.TopWriters, err = m.getTopPeople(RoleWriter, 10, inScope, args); err != nil {
+		return stats, err
+	}
+
+	// Top genres, countries and primary languages
+	if stats.TopGenres, err = m.getTopTerms(genreTable, 10, inScope, args); err != nil {
+		return stats, err
+	}
+	if stats.TopCountries, err = m.getTopTerms(countryTable, 10, inScope, args); err != nil {
+		return stats, err
+	}
+	if stats.TopLanguages, err = m.getTopTerms(languageTable, 10, inScope, args); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+// countByYear runs a query selecting (year, count) rows
+func (m *MovieDB) countByYear(query string, args ...interface{}) ([]YearCount, error) {
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []YearCount{}
+	for rows.Next() {
+		var count YearCount
+		if err := rows.Scan(&count.Year, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
+
+// getTopPeople returns top N people (directors, actors, or writers)
+// by counting the movies in scope they are credited on.
+// role must be one of: RoleDirector, RoleActor, RoleWriter
+func (m *MovieDB) getTopPeople(role string, limit int, inScope string, args []interface{}) ([]PersonStat, error) {
+	query := `
+	SELECT p.slug, p.name, COUNT(DISTINCT c.movie_id) AS movie_count
+	FROM credits c
+	JOIN people p ON p.slug = c.person_id
+	WHERE c.role = ? AND c.movie_id IN (` + inScope + `)
+	GROUP BY p.slug
+	ORDER BY movie_count DESC, p.name ASC
+	LIMIT ?
+	`
+
+	params := append(append([]interface{}{role}, args...), limit)
+	rows, err := m.db.Query(query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top %ss: %w", role, err)
+	}
+	defer rows.Close()
+
+	results := []PersonStat{}
+	for rows.Next() {
+		var person PersonStat
+		if err := rows.Scan(&person.Slug, &person.Name, &person.MovieCount); err != nil {
+			return nil, fmt.Errorf("failed to scan %s row: %w", role, err)
+		}
+		results = append(results, person)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return results, nil
+}
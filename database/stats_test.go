@@ -0,0 +1,102 @@
+package database
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// addMovies stores each movie, failing the test on the first error
+func addMovies(t *testing.T, db *MovieDB, movies ...Movie) {
+	t.Helper()
+	for _, movie := range movies {
+		if movie.LetterboxdURL == "" {
+			movie.LetterboxdURL = movie.LetterboxdID + "/"
+		}
+		if err := db.AddMovie(movie); err != nil {
+			t.Fatalf("AddMovie(%s): %v", movie.LetterboxdID, err)
+		}
+	}
+}
+
+func TestGetStatsEmptyCollection(t *testing.T) {
+	db := newTestDB(t)
+
+	stats, err := db.GetStats(StatsFilter{})
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.TotalMovies != 0 || stats.TotalViewings != 0 || stats.TotalLiked != 0 {
+		t.Errorf("totals = %d movies, %d viewings, %d liked, want 0", stats.TotalMovies, stats.TotalViewings, stats.TotalLiked)
+	}
+	if stats.AverageRating != 0 || stats.AverageLetterboxdRating != 0 || stats.AverageRuntimeMinutes != 0 {
+		t.Errorf("averages = %v, %v, %d, want 0", stats.AverageRating, stats.AverageLetterboxdRating, stats.AverageRuntimeMinutes)
+	}
+	if stats.TotalRuntimeFormatted != "0 days, 0 hours, 0 minutes" {
+		t.Errorf("runtime = %q", stats.TotalRuntimeFormatted)
+	}
+
+	// Lists are empty rather than nil, so they encode as []
+	if stats.ViewingsByYear == nil || stats.MoviesByYear == nil || stats.TopMovies == nil ||
+		stats.TopDirectors == nil || stats.TopGenres == nil || stats.ReleaseYears == nil {
+		t.Errorf("nil list in %+v", stats)
+	}
+}
+
+func TestGetStatsAverages(t *testing.T) {
+	db := newTestDB(t)
+
+	addMovies(t, db,
+		Movie{LetterboxdID: "/film/paterson", Title: "Paterson", Year: 2016, Rating: 4, LetterboxdRating: 3.9, Length: 120, Liked: true},
+		Movie{LetterboxdID: "/film/amelie", Title: "Amélie", Year: 2001, Rating: 3, LetterboxdRating: 3.5, Length: 90},
+		// Watched but not rated
+		Movie{LetterboxdID: "/film/ghost", Title: "Ghost", Year: 1990, LetterboxdRating: 4.1, Length: 100},
+		// Rated but not scraped yet: no Letterboxd rating or runtime
+		Movie{LetterboxdID: "/film/heat", Title: "Heat", Rating: 5},
+	)
+	watched := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	err := db.AddViewing(Viewing{
+		LetterboxdViewingID: ViewingKey("/film/paterson", watched),
+		LetterboxdID:        "/film/paterson",
+		WatchedDate:         watched,
+		Rewatch:             true,
+	})
+	if err != nil {
+		t.Fatalf("AddViewing: %v", err)
+	}
+
+	stats, err := db.GetStats(StatsFilter{})
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+
+	if stats.TotalMovies != 4 || stats.TotalLiked != 1 || stats.TotalViewings != 1 || stats.TotalRewatches != 1 {
+		t.Errorf("totals = %d movies, %d liked, %d viewings, %d rewatches, want 4, 1, 1, 1",
+			stats.TotalMovies, stats.TotalLiked, stats.TotalViewings, stats.TotalRewatches)
+	}
+	// (4 + 3 + 5) / 3: the unrated film is left out
+	if stats.AverageRating != 4 {
+		t.Errorf("average rating = %v, want 4", stats.AverageRating)
+	}
+	// (3.9 + 3.5 + 4.1) / 3: the unscraped film is left out
+	if math.Abs(stats.AverageLetterboxdRating-3.8333) > 0.001 {
+		t.Errorf("average Letterboxd rating = %v, want 3.83", stats.AverageLetterboxdRating)
+	}
+	if stats.TotalRuntimeMinutes != 310 || stats.TotalRuntimeFormatted != "0 days, 5 hours, 10 minutes" {
+		t.Errorf("runtime = %d (%q), want 310", stats.TotalRuntimeMinutes, stats.TotalRuntimeFormatted)
+	}
+	// 310 / 3, truncated
+	if stats.AverageRuntimeMinutes != 103 || stats.AverageRuntimeFormatted != "1 hours, 43 minutes" {
+		t.Errorf("average runtime = %d (%q), want 103", stats.AverageRuntimeMinutes, stats.AverageRuntimeFormatted)
+	}
+
+	// A filter narrows the averages to its scope
+	stats, err = db.GetStats(StatsFilter{RatingMin: 4})
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.TotalMovies != 2 || stats.AverageRating != 4.5 || stats.AverageLetterboxdRating != 3.9 || stats.AverageRuntimeMinutes != 120 {
+		t.Errorf("filtered = %d movies, averages %v, %v, %d, want 2, 4.5, 3.9, 120",
+			stats.TotalMovies, stats.AverageRating, stats.AverageLetterboxdRating, stats.AverageRuntimeMinutes)
+	}
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RecordSync stores a completed sync for username at the given time.
+// full marks a sync that walked every page rather than stopping early
+func (m *MovieDB) RecordSync(username string, at time.Time, full bool) error {
+	query := `
+	INSERT INTO sync_state (username, last_synced_at, last_full_sync_at)
+	VALUES (?, ?, CASE WHEN ? THEN ? END)
+	ON CONFLICT(username) DO UPDATE SET
+		last_synced_at = excluded.last_synced_at,
+		last_full_sync_at = COALESCE(excluded.last_full_sync_at, sync_state.last_full_sync_at)
+	`
+
+	stamp := at.Format(time.RFC3339)
+	_, err := m.db.Exec(query, strings.ToLower(username), stamp, full, stamp)
+	if err != nil {
+		return fmt.Errorf("failed to record sync: %w", err)
+	}
+
+	return nil
+}
+
+// GetSyncState returns the sync record for username.
+// found is false if the user was never synced
+func (m *MovieDB) GetSyncState(username string) (state SyncState, found bool, err error) {
+	var lastSynced, lastFull sql.NullString
+
+	query := "SELECT username, last_synced_at, last_full_sync_at FROM sync_state WHERE username = ?"
+	err = m.db.QueryRow(query, strings.ToLower(username)).Scan(&state.Username, &lastSynced, &lastFull)
+	if err == sql.ErrNoRows {
+		return SyncState{Username: username}, false, nil
+	}
+	if err != nil {
+		return state, false, fmt.Errorf("failed to get sync state: %w", err)
+	}
+
+	if parsed, err := time.Parse(time.RFC3339, lastSynced.String); err == nil {
+		state.LastSyncedAt = parsed
+	}
+	if parsed, err := time.Parse(time.RFC3339, lastFull.String); err == nil {
+		state.LastFullSyncAt = parsed
+	}
+
+	return state, true, nil
+}
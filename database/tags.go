@@ -0,0 +1,205 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TagStat is a diary tag with the number of viewings carrying it and the
+// number of distinct films among them
+type TagStat struct {
+	Slug     string `json:"slug"`
+	Name     string `json:"name"`
+	Viewings int    `json:"viewings"`
+	Films    int    `json:"films"`
+}
+
+// TagPair is two tags used together on the same viewings. Tag sorts
+// before Other by slug
+type TagPair struct {
+	Tag      Term `json:"tag"`
+	Other    Term `json:"other"`
+	Viewings int  `json:"viewings"`
+}
+
+// TagSlug builds the slug Letterboxd uses for a tag name
+// From: "Criterion Channel" -> "criterion-channel"
+func TagSlug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// saveViewingTags replaces the tags of a viewing inside the given
+// transaction, creating or renaming tags as needed
+func saveViewingTags(tx *sql.Tx, viewingKey string, tags []Term) error {
+	var viewingID int64
+	if err := tx.QueryRow("SELECT id FROM viewings WHERE letterboxd_viewing_id = ?", viewingKey).Scan(&viewingID); err != nil {
+		return fmt.Errorf("failed to find viewing %s: %w", viewingKey, err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM viewing_tags WHERE viewing_id = ?", viewingID); err != nil {
+		return fmt.Errorf("failed to clear tags: %w", err)
+	}
+
+	tagStmt, err := tx.Prepare(`
+	INSERT INTO tags (slug, name) VALUES (?, ?)
+	ON CONFLICT(slug) DO UPDATE SET name = excluded.name
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare tags statement: %w", err)
+	}
+	defer tagStmt.Close()
+
+	linkStmt, err := tx.Prepare(`
+	INSERT OR IGNORE INTO viewing_tags (viewing_id, tag_id, position)
+	VALUES (?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare viewing_tags statement: %w", err)
+	}
+	defer linkStmt.Close()
+
+	for i, tag := range tags {
+		if tag.Slug == "" {
+			continue
+		}
+		if _, err := tagStmt.Exec(tag.Slug, tag.Name); err != nil {
+			return fmt.Errorf("failed to save tag %s: %w", tag.Slug, err)
+		}
+		if _, err := linkStmt.Exec(viewingID, tag.Slug, i); err != nil {
+			return fmt.Errorf("failed to link tag %s: %w", tag.Slug, err)
+		}
+	}
+
+	return nil
+}
+
+// getViewingTags returns the tags of a viewing, in the order they were given
+func (m *MovieDB) getViewingTags(viewingID int64) ([]Term, error) {
+	rows, err := m.db.Query(`
+	SELECT t.slug, t.name
+	FROM viewing_tags vt
+	JOIN tags t ON t.slug = vt.tag_id
+	WHERE vt.viewing_id = ?
+	ORDER BY vt.position ASC
+	`, viewingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []Term{}
+	for rows.Next() {
+		var tag Term
+		if err := rows.Scan(&tag.Slug, &tag.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan tag row: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return tags, nil
+}
+
+// GetTagCounts counts the viewings and films carrying each tag among the
+// movies matching filter, most used first
+func (m *MovieDB) GetTagCounts(filter StatsFilter) ([]TagStat, error) {
+	where, args, err := filter.movieQuery().where()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query(`
+	SELECT t.slug, t.name, COUNT(*) AS viewing_count, COUNT(DISTINCT v.movie_id)
+	FROM viewing_tags vt
+	JOIN tags t ON t.slug = vt.tag_id
+	JOIN viewings v ON v.id = vt.viewing_id
+	WHERE v.movie_id IN (SELECT letterboxd_id FROM movies `+where+`)
+	GROUP BY t.slug
+	ORDER BY viewing_count DESC, t.name ASC
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tags: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []TagStat{}
+	for rows.Next() {
+		var stat TagStat
+		if err := rows.Scan(&stat.Slug, &stat.Name, &stat.Viewings, &stat.Films); err != nil {
+			return nil, fmt.Errorf("failed to scan tag count row: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetTagCooccurrence returns the pairs of tags most often used together on
+// one viewing. An empty tag covers every pair, otherwise only pairs
+// including that tag. limit <= 0 returns every pair
+func (m *MovieDB) GetTagCooccurrence(tag string, limit int) ([]TagPair, error) {
+	query := `
+	SELECT a.slug, a.name, b.slug, b.name, COUNT(*) AS viewing_count
+	FROM viewing_tags x
+	JOIN viewing_tags y ON y.viewing_id = x.viewing_id AND y.tag_id > x.tag_id
+	JOIN tags a ON a.slug = x.tag_id
+	JOIN tags b ON b.slug = y.tag_id
+	`
+	var args []interface{}
+	if tag != "" {
+		query += "WHERE x.tag_id = ? OR y.tag_id = ?\n"
+		args = append(args, tag, tag)
+	}
+	query += `
+	GROUP BY a.slug, b.slug
+	ORDER BY viewing_count DESC, a.slug ASC, b.slug ASC
+	`
+	if limit > 0 {
+		query += "LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tag co-occurrence: %w", err)
+	}
+	defer rows.Close()
+
+	pairs := []TagPair{}
+	for rows.Next() {
+		var pair TagPair
+		err := rows.Scan(&pair.Tag.Slug, &pair.Tag.Name, &pair.Other.Slug, &pair.Other.Name, &pair.Viewings)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag pair row: %w", err)
+		}
+		pairs = append(pairs, pair)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return pairs, nil
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTagSlug(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Criterion Channel", "criterion-channel"},
+		{"theatre", "theatre"},
+		{"  with   family  ", "with-family"},
+		{"rewatch #2!", "rewatch-2"},
+		{"Café", "café"},
+		{"---", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := TagSlug(tt.name); got != tt.want {
+			t.Errorf("TagSlug(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGetTagCooccurrence(t *testing.T) {
+	db := newTestDB(t)
+	addMovies(t, db, Movie{LetterboxdID: "/film/paterson", Title: "Paterson"})
+
+	for i, names := range [][]string{
+		{"theatre", "with family", "IMAX"},
+		{"theatre", "with family"},
+		{"theatre", "solo"},
+	} {
+		var tags []Term
+		for _, name := range names {
+			tags = append(tags, Term{Slug: TagSlug(name), Name: name})
+		}
+		watched := time.Date(2024, 3, 15+i, 0, 0, 0, 0, time.UTC)
+		err := db.AddViewing(Viewing{
+			LetterboxdViewingID: ViewingKey("/film/paterson", watched),
+			LetterboxdID:        "/film/paterson",
+			WatchedDate:         watched,
+			Tags:                tags,
+		})
+		if err != nil {
+			t.Fatalf("AddViewing: %v", err)
+		}
+	}
+
+	tests := []struct {
+		tag   string
+		limit int
+		want  []string
+	}{
+		{"", 0, []string{"theatre+with-family:2", "imax+theatre:1", "imax+with-family:1", "solo+theatre:1"}},
+		{"", 2, []string{"theatre+with-family:2", "imax+theatre:1"}},
+		{"with-family", 0, []string{"theatre+with-family:2", "imax+with-family:1"}},
+		{"theatre", 1, []string{"theatre+with-family:2"}},
+		{"solo", 5, []string{"solo+theatre:1"}},
+		{"unused", 0, []string{}},
+	}
+
+	for _, tt := range tests {
+		pairs, err := db.GetTagCooccurrence(tt.tag, tt.limit)
+		if err != nil {
+			t.Fatalf("GetTagCooccurrence(%q, %d): %v", tt.tag, tt.limit, err)
+		}
+		got := []string{}
+		for _, pair := range pairs {
+			got = append(got, fmt.Sprintf("%s+%s:%d", pair.Tag.Slug, pair.Other.Slug, pair.Viewings))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetTagCooccurrence(%q, %d) = %v, want %v", tt.tag, tt.limit, got, tt.want)
+		}
+	}
+}
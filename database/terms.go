@@ -0,0 +1,217 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// termTable names a classification lookup table and the table linking it to movies
+type termTable struct {
+	lookup string
+	link   string
+	column string
+}
+
+var (
+	genreTable    = termTable{lookup: "genres", link: "movie_genres", column: "genre_id"}
+	countryTable  = termTable{lookup: "countries", link: "movie_countries", column: "country_id"}
+	languageTable = termTable{lookup: "languages", link: "movie_languages", column: "language_id"}
+	studioTable   = termTable{lookup: "studios", link: "movie_studios", column: "studio_id"}
+	themeTable    = termTable{lookup: "themes", link: "movie_themes", column: "theme_id"}
+)
+
+// hasTerms reports whether a movie carries any classification,
+// which is only the case when its film page was scraped
+func hasTerms(movie Movie) bool {
+	return len(movie.Genres) > 0 || len(movie.Countries) > 0 || movie.PrimaryLanguage != nil ||
+		len(movie.Languages) > 0 || len(movie.Studios) > 0 || len(movie.Themes) > 0
+}
+
+// saveTerms replaces the genres, countries, languages, studios and themes
+// of a movie inside the given transaction
+func saveTerms(tx *sql.Tx, movie Movie) error {
+	sets := []struct {
+		table termTable
+		terms []Term
+	}{
+		{genreTable, movie.Genres},
+		{countryTable, movie.Countries},
+		{studioTable, movie.Studios},
+		{themeTable, movie.Themes},
+	}
+	for _, set := range sets {
+		if err := saveTermLinks(tx, set.table, movie.LetterboxdID, set.terms); err != nil {
+			return err
+		}
+	}
+
+	// The primary language goes first and is flagged, the rest are spoken languages
+	languages := movie.Languages
+	if movie.PrimaryLanguage != nil {
+		languages = append([]Term{*movie.PrimaryLanguage}, languages...)
+	}
+	if err := saveTermLinks(tx, languageTable, movie.LetterboxdID, languages); err != nil {
+		return err
+	}
+	if movie.PrimaryLanguage != nil {
+		_, err := tx.Exec("UPDATE movie_languages SET is_primary = 1 WHERE movie_id = ? AND language_id = ?",
+			movie.LetterboxdID, movie.PrimaryLanguage.Slug)
+		if err != nil {
+			return fmt.Errorf("failed to mark primary language: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// saveTermLinks replaces the links between a movie and one lookup table,
+// creating or renaming lookup entries as needed
+func saveTermLinks(tx *sql.Tx, table termTable, movieID string, terms []Term) error {
+	if _, err := tx.Exec("DELETE FROM "+table.link+" WHERE movie_id = ?", movieID); err != nil {
+		return fmt.Errorf("failed to clear %s: %w", table.lookup, err)
+	}
+
+	lookupStmt, err := tx.Prepare(`
+	INSERT INTO ` + table.lookup + ` (slug, name) VALUES (?, ?)
+	ON CONFLICT(slug) DO UPDATE SET name = excluded.name
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare %s statement: %w", table.lookup, err)
+	}
+	defer lookupStmt.Close()
+
+	linkStmt, err := tx.Prepare(`
+	INSERT OR IGNORE INTO ` + table.link + ` (movie_id, ` + table.column + `, position)
+	VALUES (?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare %s statement: %w", table.link, err)
+	}
+	defer linkStmt.Close()
+
+	for i, term := range terms {
+		if term.Slug == "" {
+			continue
+		}
+		if _, err := lookupStmt.Exec(term.Slug, term.Name); err != nil {
+			return fmt.Errorf("failed to save %s %s: %w", table.lookup, term.Slug, err)
+		}
+		if _, err := linkStmt.Exec(movieID, term.Slug, i); err != nil {
+			return fmt.Errorf("failed to link %s %s: %w", table.lookup, term.Slug, err)
+		}
+	}
+
+	return nil
+}
+
+// LoadTerms fills in the genres, countries, languages, studios and themes of a movie
+func (m *MovieDB) LoadTerms(movie *Movie) error {
+	var err error
+	if movie.Genres, err = m.getTerms(genreTable, movie.LetterboxdID); err != nil {
+		return err
+	}
+	if movie.Countries, err = m.getTerms(countryTable, movie.LetterboxdID); err != nil {
+		return err
+	}
+	if movie.Studios, err = m.getTerms(studioTable, movie.LetterboxdID); err != nil {
+		return err
+	}
+	if movie.Themes, err = m.getTerms(themeTable, movie.LetterboxdID); err != nil {
+		return err
+	}
+
+	var primary Term
+	err = m.db.QueryRow(`
+	SELECT t.slug, t.name
+	FROM movie_languages l
+	JOIN languages t ON t.slug = l.language_id
+	WHERE l.movie_id = ? AND l.is_primary = 1
+	`, movie.LetterboxdID).Scan(&primary.Slug, &primary.Name)
+	switch {
+	case err == sql.ErrNoRows:
+		movie.PrimaryLanguage = nil
+	case err != nil:
+		return fmt.Errorf("failed to query primary language: %w", err)
+	default:
+		movie.PrimaryLanguage = &primary
+	}
+
+	if movie.Languages, err = m.getTerms(languageTable, movie.LetterboxdID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getTerms returns the entries of one lookup table linked to a movie, in page order
+func (m *MovieDB) getTerms(table termTable, movieID string) ([]Term, error) {
+	query := `
+	SELECT t.slug, t.name
+	FROM ` + table.link + ` l
+	JOIN ` + table.lookup + ` t ON t.slug = l.` + table.column + `
+	WHERE l.movie_id = ?
+	ORDER BY l.position ASC
+	`
+
+	rows, err := m.db.Query(query, movieID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", table.lookup, err)
+	}
+	defer rows.Close()
+
+	var terms []Term
+	for rows.Next() {
+		var term Term
+		if err := rows.Scan(&term.Slug, &term.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan %s row: %w", table.lookup, err)
+		}
+		terms = append(terms, term)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return terms, nil
+}
+
+// getTopTerms returns the most common entries of a lookup table among the
+// movies in scope. For languages only the primary language of each movie counts
+func (m *MovieDB) getTopTerms(table termTable, limit int, inScope string, args []interface{}) ([]TermStat, error) {
+	filter := ""
+	if table == languageTable {
+		filter = "AND l.is_primary = 1"
+	}
+
+	query := `
+	SELECT t.slug, t.name, COUNT(DISTINCT l.movie_id) AS movie_count
+	FROM ` + table.link + ` l
+	JOIN ` + table.lookup + ` t ON t.slug = l.` + table.column + `
+	WHERE l.movie_id IN (` + inScope + `) ` + filter + `
+	GROUP BY t.slug
+	ORDER BY movie_count DESC, t.name ASC
+	LIMIT ?
+	`
+
+	params := append(append([]interface{}{}, args...), limit)
+	rows, err := m.db.Query(query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top %s: %w", table.lookup, err)
+	}
+	defer rows.Close()
+
+	results := []TermStat{}
+	for rows.Next() {
+		var term TermStat
+		if err := rows.Scan(&term.Slug, &term.Name, &term.MovieCount); err != nil {
+			return nil, fmt.Errorf("failed to scan %s row: %w", table.lookup, err)
+		}
+		results = append(results, term)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return results, nil
+}
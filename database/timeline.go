@@ -0,0 +1,59 @@
+package database
+
+import "fmt"
+
+// TimelinePoint summarises the movies released in one year or decade.
+// Averages skip unrated movies and are 0 when none are rated
+type TimelinePoint struct {
+	Year                    int     `json:"year"`
+	Count                   int     `json:"count"`
+	AverageRating           float64 `json:"average_rating"`
+	AverageLetterboxdRating float64 `json:"average_letterboxd_rating"`
+	TotalRuntimeMinutes     int     `json:"total_runtime_minutes"`
+}
+
+// getTimeline groups the movies in scope by release period, width years
+// wide (1 or 10), from the earliest to the latest with empty periods filled
+// in, so the series can be charted as is. Year is the first year of each period
+func (m *MovieDB) getTimeline(width int, inScope string, args []interface{}) ([]TimelinePoint, error) {
+	params := append([]interface{}{width, width}, args...)
+	rows, err := m.db.Query(`
+		SELECT (year / ?) * ? AS period, COUNT(*),
+			COALESCE(AVG(CASE WHEN rating > 0 THEN rating END), 0),
+			COALESCE(AVG(CASE WHEN letterboxd_rating > 0 THEN letterboxd_rating END), 0),
+			COALESCE(SUM(length), 0)
+		FROM movies
+		WHERE year > 0 AND letterboxd_id IN (`+inScope+`)
+		GROUP BY period
+		ORDER BY period ASC
+	`, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query release timeline: %w", err)
+	}
+	defer rows.Close()
+
+	timeline := []TimelinePoint{}
+	for rows.Next() {
+		var point TimelinePoint
+		if err := rows.Scan(&point.Year, &point.Count, &point.AverageRating,
+			&point.AverageLetterboxdRating, &point.TotalRuntimeMinutes); err != nil {
+			return nil, fmt.Errorf("failed to scan timeline row: %w", err)
+		}
+		point.AverageRating = round2(point.AverageRating)
+		point.AverageLetterboxdRating = round2(point.AverageLetterboxdRating)
+
+		// Fill the gap since the previous period with empty points
+		if n := len(timeline); n > 0 {
+			for year := timeline[n-1].Year + width; year < point.Year; year += width {
+				timeline = append(timeline, TimelinePoint{Year: year})
+			}
+		}
+		timeline = append(timeline, point)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return timeline, nil
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestReleaseTimeline(t *testing.T) {
+	db := newTestDB(t)
+
+	addMovies(t, db,
+		Movie{LetterboxdID: "/film/barry-lyndon", Title: "Barry Lyndon", Year: 1975, Rating: 4, LetterboxdRating: 3, Length: 100},
+		Movie{LetterboxdID: "/film/days-of-heaven", Title: "Days of Heaven", Year: 1978, LetterboxdRating: 3.5},
+		// Not scraped yet
+		Movie{LetterboxdID: "/film/halloween", Title: "Halloween", Year: 1978, Rating: 5},
+		Movie{LetterboxdID: "/film/thief", Title: "Thief", Year: 1981, Rating: 3, LetterboxdRating: 4, Length: 90},
+		// No release year, so on neither timeline
+		Movie{LetterboxdID: "/film/untitled", Title: "Untitled", Rating: 1},
+	)
+
+	stats, err := db.GetStats(StatsFilter{})
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  []TimelinePoint
+		want []TimelinePoint
+	}{
+		{"by year", stats.ReleaseYears, []TimelinePoint{
+			{Year: 1975, Count: 1, AverageRating: 4, AverageLetterboxdRating: 3, TotalRuntimeMinutes: 100},
+			{Year: 1976},
+			{Year: 1977},
+			{Year: 1978, Count: 2, AverageRating: 5, AverageLetterboxdRating: 3.5},
+			{Year: 1979},
+			{Year: 1980},
+			{Year: 1981, Count: 1, AverageRating: 3, AverageLetterboxdRating: 4, TotalRuntimeMinutes: 90},
+		}},
+		{"by decade", stats.ReleaseDecades, []TimelinePoint{
+			{Year: 1970, Count: 3, AverageRating: 4.5, AverageLetterboxdRating: 3.25, TotalRuntimeMinutes: 100},
+			{Year: 1980, Count: 1, AverageRating: 3, AverageLetterboxdRating: 4, TotalRuntimeMinutes: 90},
+		}},
+	}
+	for _, tt := range tests {
+		if len(tt.got) != len(tt.want) {
+			t.Errorf("%s = %+v, want %+v", tt.name, tt.got, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if tt.got[i] != tt.want[i] {
+				t.Errorf("%s[%d] = %+v, want %+v", tt.name, i, tt.got[i], tt.want[i])
+			}
+		}
+	}
+
+	// A filter limits the timeline to its films, without padding outside them
+	stats, err = db.GetStats(StatsFilter{RatingMin: 4})
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if len(stats.ReleaseYears) != 4 || stats.ReleaseYears[0].Year != 1975 || stats.ReleaseYears[3].Year != 1978 {
+		t.Errorf("filtered by year = %+v, want 1975 to 1978", stats.ReleaseYears)
+	}
+	if len(stats.ReleaseDecades) != 1 || stats.ReleaseDecades[0].Count != 2 {
+		t.Errorf("filtered by decade = %+v, want the 1970s with 2 films", stats.ReleaseDecades)
+	}
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// diaryDateLayout is the format used to store watched dates
+const diaryDateLayout = "2006-01-02"
+
+// ViewingKey builds the identifier used to deduplicate viewings.
+// The scraper and the export importer both derive it from the film and
+// watched date, so importing the same diary entry twice updates one row
+func ViewingKey(letterboxdID string, watched time.Time) string {
+	return fmt.Sprintf("%s:%s", letterboxdID, watched.Format(diaryDateLayout))
+}
+
+// AddViewing inserts a diary viewing, or updates it if the same
+// Letterboxd viewing was already imported, and replaces its tags
+func (m *MovieDB) AddViewing(viewing Viewing) error {
+	query := `
+	INSERT INTO viewings (
+		letterboxd_viewing_id, movie_id, watched_date, rewatch, rating, review_url
+	) VALUES (?, ?, ?, ?, ?, ?)
+	ON CONFLICT(letterboxd_viewing_id) DO UPDATE SET
+		watched_date = excluded.watched_date,
+		rewatch = excluded.rewatch,
+		rating = excluded.rating,
+		review_url = COALESCE(NULLIF(excluded.review_url, ''), viewings.review_url)
+	`
+
+	var rating sql.NullFloat64
+	if viewing.Rating > 0 {
+		rating = sql.NullFloat64{Float64: viewing.Rating, Valid: true}
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(query,
+		viewing.LetterboxdViewingID, viewing.LetterboxdID, viewing.WatchedDate.Format(diaryDateLayout),
+		viewing.Rewatch, rating, viewing.ReviewURL,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save viewing: %w", err)
+	}
+
+	if viewing.Tags != nil {
+		if err := saveViewingTags(tx, viewing.LetterboxdViewingID, viewing.Tags); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit viewing: %w", err)
+	}
+
+	return nil
+}
+
+// ViewingExists checks if a viewing with the given key was already imported
+func (m *MovieDB) ViewingExists(viewingKey string) (bool, error) {
+	var count int
+
+	query := "SELECT COUNT(*) FROM viewings WHERE letterboxd_viewing_id = ?"
+	if err := m.db.QueryRow(query, viewingKey).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check if viewing exists: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// GetViewings retrieves every viewing of a movie, most recent first
+func (m *MovieDB) GetViewings(letterboxdID string) ([]Viewing, error) {
+	query := `
+	SELECT id, letterboxd_viewing_id, movie_id, watched_date, rewatch, rating, review_url
+	FROM viewings
+	WHERE movie_id = ?
+	ORDER BY watched_date DESC, id DESC
+	`
+
+	return m.queryViewings(query, letterboxdID)
+}
+
+// GetViewingsBetween retrieves viewings watched within [from, to], most recent first
+func (m *MovieDB) GetViewingsBetween(from, to time.Time) ([]Viewing, error) {
+	query := `
+	SELECT id, letterboxd_viewing_id, movie_id, watched_date, rewatch, rating, review_url
+	FROM viewings
+	WHERE watched_date BETWEEN ? AND ?
+	ORDER BY watched_date DESC, id DESC
+	`
+
+	return m.queryViewings(query, from.Format(diaryDateLayout), to.Format(diaryDateLayout))
+}
+
+// queryViewings runs a viewings SELECT and scans every row
+func (m *MovieDB) queryViewings(query string, args ...interface{}) ([]Viewing, error) {
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query viewings: %w", err)
+	}
+	defer rows.Close()
+
+	var viewings []Viewing
+	for rows.Next() {
+		var viewing Viewing
+		var watchedDate string
+		var rating sql.NullFloat64
+		var reviewURL sql.NullString
+
+		err := rows.Scan(
+			&viewing.ID,
+			&viewing.LetterboxdViewingID,
+			&viewing.LetterboxdID,
+			&watchedDate,
+			&viewing.Rewatch,
+			&rating,
+			&reviewURL,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan viewing row: %w", err)
+		}
+
+		if parsed, err := time.Parse(diaryDateLayout, watchedDate); err == nil {
+			viewing.WatchedDate = parsed
+		}
+		viewing.Rating = rating.Float64
+		viewing.ReviewURL = reviewURL.String
+
+		viewings = append(viewings, viewing)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	rows.Close()
+
+	for i := range viewings {
+		if viewings[i].Tags, err = m.getViewingTags(viewings[i].ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return viewings, nil
+}
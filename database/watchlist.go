@@ -0,0 +1,325 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SortPosition orders the watchlist as Letterboxd does, most recently added first
+const SortPosition = "position"
+
+// watchlistColumns selects watchlist rows in the shape scanMovie expects.
+// The watchlist has no personal rating or like
+const watchlistColumns = `letterboxd_id, title, year, letterboxd_url, NULL AS rating, letterboxd_rating,
+		   length, date_added, poster_url, director, "cast", writers, letterboxd_uri,
+		   details_scraped_at, tmdb_id, tmdb_type, imdb_id, synopsis, 0 AS liked`
+
+// WatchlistEntry is a film on the watchlist. Movie.DateAdded is when a sync
+// first saw it there; Position is its place on the watchlist, from 1
+type WatchlistEntry struct {
+	Movie    Movie `json:"movie"`
+	Position int   `json:"position"`
+}
+
+// WatchlistQuery filters, sorts and pages the watchlist like MovieQuery.
+// The default order is by Position
+type WatchlistQuery struct {
+	Text                string  `json:"text"`
+	YearMin             int     `json:"year_min"`
+	YearMax             int     `json:"year_max"`
+	LetterboxdRatingMin float64 `json:"letterboxd_rating_min"`
+	LetterboxdRatingMax float64 `json:"letterboxd_rating_max"`
+	LengthMin           int     `json:"length_min"`
+	LengthMax           int     `json:"length_max"`
+	AddedFrom           string  `json:"added_from"`
+	AddedTo             string  `json:"added_to"`
+	Sort                string  `json:"sort"`
+	Descending          bool    `json:"descending"`
+	Limit               int     `json:"limit"`
+	Offset              int     `json:"offset"`
+}
+
+// WatchlistQueryResult is one page of a WatchlistQuery and the number of
+// entries matching it across all pages
+type WatchlistQueryResult struct {
+	Entries []WatchlistEntry `json:"entries"`
+	Total   int              `json:"total"`
+}
+
+// movieQuery expresses the filters and sort as a MovieQuery,
+// whose columns the watchlist table shares
+func (q WatchlistQuery) movieQuery() MovieQuery {
+	return MovieQuery{
+		Text:                q.Text,
+		YearMin:             q.YearMin,
+		YearMax:             q.YearMax,
+		LetterboxdRatingMin: q.LetterboxdRatingMin,
+		LetterboxdRatingMax: q.LetterboxdRatingMax,
+		LengthMin:           q.LengthMin,
+		LengthMax:           q.LengthMax,
+		AddedFrom:           q.AddedFrom,
+		AddedTo:             q.AddedTo,
+		Sort:                q.Sort,
+		Descending:          q.Descending,
+	}
+}
+
+// orderBy compiles the sort key to an ORDER BY clause, by position unless
+// another key is given. The watchlist cannot be sorted by personal rating
+func (q WatchlistQuery) orderBy() (string, error) {
+	direction := "ASC"
+	if q.Descending {
+		direction = "DESC"
+	}
+
+	switch q.Sort {
+	case "", SortPosition:
+		return "ORDER BY position " + direction + ", letterboxd_id " + direction, nil
+	case SortRating:
+		return "", fmt.Errorf("unknown sort key %q", q.Sort)
+	}
+	return q.movieQuery().orderBy()
+}
+
+// SaveWatchlist replaces the watchlist with entries. Films already on it
+// keep their date added, and keep their details when the entry has none;
+// films no longer on it are removed
+func (m *MovieDB) SaveWatchlist(entries []WatchlistEntry) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := upsertWatchlistEntries(tx, entries); err != nil {
+		return err
+	}
+
+	onList := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		onList[entry.Movie.LetterboxdID] = true
+	}
+
+	// Remove films taken off the watchlist since the last sync
+	rows, err := tx.Query("SELECT letterboxd_id FROM watchlist")
+	if err != nil {
+		return fmt.Errorf("failed to query watchlist: %w", err)
+	}
+	var removed []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan watchlist row: %w", err)
+		}
+		if !onList[id] {
+			removed = append(removed, id)
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	for _, id := range removed {
+		if _, err := tx.Exec("DELETE FROM watchlist WHERE letterboxd_id = ?", id); err != nil {
+			return fmt.Errorf("failed to remove watchlist entry %s: %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// AddToWatchlist puts entries at the top of the watchlist in the given
+// order, moving the films already on it down. Nothing is removed
+func (m *MovieDB) AddToWatchlist(entries []WatchlistEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE watchlist SET position = position + ?", len(entries)); err != nil {
+		return fmt.Errorf("failed to move watchlist entries: %w", err)
+	}
+	if err := upsertWatchlistEntries(tx, entries); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// upsertWatchlistEntries inserts or updates entries within tx
+func upsertWatchlistEntries(tx *sql.Tx, entries []WatchlistEntry) error {
+	stmt, err := tx.Prepare(`
+	INSERT INTO watchlist (
+		letterboxd_id, title, year, letterboxd_url, letterboxd_rating, length, date_added,
+		poster_url, director, "cast", writers, letterboxd_uri, details_scraped_at,
+		tmdb_id, tmdb_type, imdb_id, synopsis, position
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(letterboxd_id) DO UPDATE SET
+		title = excluded.title,
+		letterboxd_url = excluded.letterboxd_url,
+		position = excluded.position,
+		year = COALESCE(NULLIF(excluded.year, 0), watchlist.year),
+		letterboxd_rating = COALESCE(excluded.letterboxd_rating, watchlist.letterboxd_rating),
+		length = COALESCE(excluded.length, watchlist.length),
+		poster_url = COALESCE(excluded.poster_url, watchlist.poster_url),
+		director = COALESCE(excluded.director, watchlist.director),
+		"cast" = COALESCE(excluded."cast", watchlist."cast"),
+		writers = COALESCE(excluded.writers, watchlist.writers),
+		letterboxd_uri = COALESCE(excluded.letterboxd_uri, watchlist.letterboxd_uri),
+		details_scraped_at = COALESCE(excluded.details_scraped_at, watchlist.details_scraped_at),
+		tmdb_id = COALESCE(excluded.tmdb_id, watchlist.tmdb_id),
+		tmdb_type = COALESCE(excluded.tmdb_type, watchlist.tmdb_type),
+		imdb_id = COALESCE(excluded.imdb_id, watchlist.imdb_id),
+		synopsis = COALESCE(excluded.synopsis, watchlist.synopsis)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare watchlist statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		movie := entry.Movie
+		if movie.DateAdded.IsZero() {
+			movie.DateAdded = time.Now()
+		}
+		if !strings.HasPrefix(movie.LetterboxdID, "boxd.it/") {
+			if err := claimImportedWatchlistEntry(tx, movie); err != nil {
+				return err
+			}
+		}
+
+		var letterboxdRating interface{}
+		if movie.LetterboxdRating > 0 {
+			letterboxdRating = movie.LetterboxdRating
+		}
+
+		_, err := stmt.Exec(
+			movie.LetterboxdID, movie.Title, movie.Year, movie.LetterboxdURL,
+			letterboxdRating, nullInt(movie.Length), movie.DateAdded.Format(time.RFC3339),
+			nullString(movie.PosterURL), nullString(movie.Director), nullString(movie.Cast),
+			nullString(movie.Writers), nullString(movie.LetterboxdURI), nullTime(movie.DetailsScrapedAt),
+			nullInt(movie.TmdbID), nullString(movie.TmdbType), nullString(movie.ImdbID),
+			nullString(movie.Synopsis), entry.Position,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save watchlist entry %s: %w", movie.LetterboxdID, err)
+		}
+	}
+
+	return nil
+}
+
+// claimImportedWatchlistEntry moves a watchlist row created by the export
+// importer, still keyed by its boxd.it link, to the film slug of movie.
+// It is matched by that link or by title and year; copies left over when
+// the slug is already on the watchlist are removed
+func claimImportedWatchlistEntry(tx *sql.Tx, movie Movie) error {
+	match := `letterboxd_id LIKE 'boxd.it/%' AND (letterboxd_uri = ?2 OR (title = ?3 COLLATE NOCASE AND year = ?4 AND ?4 > 0))`
+	args := []interface{}{movie.LetterboxdID, nullString(movie.LetterboxdURI), movie.Title, movie.Year}
+
+	_, err := tx.Exec(`
+	UPDATE watchlist SET letterboxd_id = ?1
+	WHERE rowid = (SELECT rowid FROM watchlist WHERE `+match+` LIMIT 1)
+		AND NOT EXISTS (SELECT 1 FROM watchlist WHERE letterboxd_id = ?1)
+	`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to claim imported watchlist entry for %s: %w", movie.LetterboxdID, err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM watchlist WHERE "+match, args...); err != nil {
+		return fmt.Errorf("failed to remove imported watchlist entry for %s: %w", movie.LetterboxdID, err)
+	}
+
+	return nil
+}
+
+// GetWatchlistEntry retrieves a film from the watchlist
+func (m *MovieDB) GetWatchlistEntry(letterboxdID string) (entry WatchlistEntry, found bool, err error) {
+	entries, err := m.selectWatchlist(`
+		SELECT `+watchlistColumns+`, position
+		FROM watchlist
+		WHERE letterboxd_id = ?
+	`, letterboxdID)
+	if err != nil || len(entries) == 0 {
+		return entry, false, err
+	}
+	return entries[0], true, nil
+}
+
+// QueryWatchlist returns the page of watchlist entries matching q and the
+// total match count
+func (m *MovieDB) QueryWatchlist(q WatchlistQuery) (WatchlistQueryResult, error) {
+	result := WatchlistQueryResult{Entries: []WatchlistEntry{}}
+
+	where, args, err := q.movieQuery().where()
+	if err != nil {
+		return result, err
+	}
+	orderBy, err := q.orderBy()
+	if err != nil {
+		return result, err
+	}
+
+	if err := m.db.QueryRow("SELECT COUNT(*) FROM watchlist "+where, args...).Scan(&result.Total); err != nil {
+		return result, fmt.Errorf("failed to count watchlist: %w", err)
+	}
+
+	query := "SELECT " + watchlistColumns + ", position\n\tFROM watchlist\n\t" + where + "\n\t" + orderBy
+	if q.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, q.Limit, q.Offset)
+	} else if q.Offset > 0 {
+		query += " LIMIT -1 OFFSET ?"
+		args = append(args, q.Offset)
+	}
+
+	entries, err := m.selectWatchlist(query, args...)
+	if err != nil {
+		return result, err
+	}
+	result.Entries = entries
+
+	return result, nil
+}
+
+// selectWatchlist runs a SELECT of watchlistColumns and position and scans every row
+func (m *MovieDB) selectWatchlist(query string, args ...interface{}) ([]WatchlistEntry, error) {
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watchlist: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []WatchlistEntry{}
+	for rows.Next() {
+		var entry WatchlistEntry
+		entry.Movie, err = scanMovie(rows, &entry.Position)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// watchlistOrder returns the ids and positions of the watchlist, in order
+func watchlistOrder(t *testing.T, db *MovieDB) ([]string, []int) {
+	t.Helper()
+	result, err := db.QueryWatchlist(WatchlistQuery{})
+	if err != nil {
+		t.Fatalf("QueryWatchlist: %v", err)
+	}
+	var ids []string
+	var positions []int
+	for _, entry := range result.Entries {
+		ids = append(ids, entry.Movie.LetterboxdID)
+		positions = append(positions, entry.Position)
+	}
+	return ids, positions
+}
+
+func TestWatchlistSync(t *testing.T) {
+	db := newTestDB(t)
+
+	added := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	ghost := Movie{
+		LetterboxdID:     "/film/ghost",
+		Title:            "Ghost",
+		Year:             1990,
+		LetterboxdURL:    "/film/ghost/",
+		LetterboxdRating: 3.4,
+		Length:           127,
+		Director:         "Jerry Zucker",
+		DateAdded:        added,
+	}
+	heat := Movie{LetterboxdID: "/film/heat", Title: "Heat", Year: 1995, LetterboxdURL: "/film/heat/"}
+	jaws := Movie{LetterboxdID: "/film/jaws", Title: "Jaws", Year: 1975, LetterboxdURL: "/film/jaws/"}
+
+	err := db.SaveWatchlist([]WatchlistEntry{{Movie: ghost, Position: 1}, {Movie: heat, Position: 2}, {Movie: jaws, Position: 3}})
+	if err != nil {
+		t.Fatalf("SaveWatchlist: %v", err)
+	}
+
+	// New films go on top and the rest move down
+	thief := Movie{LetterboxdID: "/film/thief", Title: "Thief", Year: 1981, LetterboxdURL: "/film/thief/"}
+	if err := db.AddToWatchlist([]WatchlistEntry{{Movie: thief, Position: 1}}); err != nil {
+		t.Fatalf("AddToWatchlist: %v", err)
+	}
+	ids, positions := watchlistOrder(t, db)
+	if want := []string{"/film/thief", "/film/ghost", "/film/heat", "/film/jaws"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("after add = %v, want %v", ids, want)
+	}
+	if want := []int{1, 2, 3, 4}; !reflect.DeepEqual(positions, want) {
+		t.Errorf("positions after add = %v, want %v", positions, want)
+	}
+
+	// A full sync drops Heat, and its Ghost entry comes from a list page
+	// without details
+	listed := Movie{LetterboxdID: "/film/ghost", Title: "Ghost", LetterboxdURL: "/film/ghost/"}
+	err = db.SaveWatchlist([]WatchlistEntry{{Movie: thief, Position: 1}, {Movie: listed, Position: 2}, {Movie: jaws, Position: 3}})
+	if err != nil {
+		t.Fatalf("SaveWatchlist: %v", err)
+	}
+	ids, positions = watchlistOrder(t, db)
+	if want := []string{"/film/thief", "/film/ghost", "/film/jaws"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("after sync = %v, want %v", ids, want)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(positions, want) {
+		t.Errorf("positions after sync = %v, want %v", positions, want)
+	}
+	if _, found, _ := db.GetWatchlistEntry("/film/heat"); found {
+		t.Error("heat still on the watchlist")
+	}
+
+	entry, found, err := db.GetWatchlistEntry("/film/ghost")
+	if err != nil || !found {
+		t.Fatalf("GetWatchlistEntry = found %v, err %v", found, err)
+	}
+	got := entry.Movie
+	if got.Year != ghost.Year || got.LetterboxdRating != ghost.LetterboxdRating || got.Length != ghost.Length || got.Director != ghost.Director {
+		t.Errorf("ghost = %+v, details not kept", got)
+	}
+	if !got.DateAdded.Equal(added) {
+		t.Errorf("date added = %v, want %v", got.DateAdded, added)
+	}
+}
@@ -0,0 +1,161 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// YearInReview summarises one calendar year of diary viewings
+type YearInReview struct {
+	Year          int            `json:"year"`
+	FilmsWatched  int            `json:"films_watched"`
+	Viewings      int            `json:"viewings"`
+	Rewatches     int            `json:"rewatches"`
+	TotalMinutes  int            `json:"total_minutes"`
+	Hours         float64        `json:"hours"`
+	HighestRated  []Movie        `json:"highest_rated"`
+	TopDirectors  []PersonStat   `json:"top_directors"`
+	TopActors     []PersonStat   `json:"top_actors"`
+	ByMonth       []MonthCount   `json:"by_month"`
+	ByDecade      []DecadeCount  `json:"by_decade"`
+	ByGenre       []TermStat     `json:"by_genre"`
+	Disagreements []Disagreement `json:"disagreements"`
+	Reviews       []Review       `json:"reviews"`
+}
+
+// MonthCount is the number of viewings in one month (1-12)
+type MonthCount struct {
+	Month int `json:"month"`
+	Count int `json:"count"`
+}
+
+// DecadeCount is the number of films released in one decade, e.g. 1970
+type DecadeCount struct {
+	Decade int `json:"decade"`
+	Count  int `json:"count"`
+}
+
+// GetYearInReview summarises the films logged in the diary during year.
+// Films without a diary entry have no watch date and are not counted
+func (m *MovieDB) GetYearInReview(year int) (YearInReview, error) {
+	review := YearInReview{
+		Year:          year,
+		HighestRated:  []Movie{},
+		ByMonth:       make([]MonthCount, 12),
+		ByDecade:      []DecadeCount{},
+		Disagreements: []Disagreement{},
+		Reviews:       []Review{},
+	}
+	for i := range review.ByMonth {
+		review.ByMonth[i].Month = i + 1
+	}
+
+	from := fmt.Sprintf("%04d-01-01", year)
+	to := fmt.Sprintf("%04d-01-01", year+1)
+	args := []interface{}{from, to}
+	// Subquery selecting the ids of the films watched during the year
+	inScope := "SELECT movie_id FROM viewings WHERE watched_date >= ? AND watched_date < ?"
+
+	// Each viewing counts towards the watch time, rewatches included
+	err := m.db.QueryRow(`
+		SELECT COUNT(*), COUNT(DISTINCT v.movie_id), COALESCE(SUM(v.rewatch), 0), COALESCE(SUM(m.length), 0)
+		FROM viewings v
+		JOIN movies m ON m.letterboxd_id = v.movie_id
+		WHERE v.watched_date >= ? AND v.watched_date < ?
+	`, from, to).Scan(&review.Viewings, &review.FilmsWatched, &review.Rewatches, &review.TotalMinutes)
+	if err != nil {
+		return review, fmt.Errorf("failed to get year totals: %w", err)
+	}
+	review.Hours = math.Round(float64(review.TotalMinutes)/60*10) / 10
+
+	rows, err := m.db.Query(`
+		SELECT CAST(substr(watched_date, 6, 2) AS INTEGER) AS month, COUNT(*)
+		FROM viewings
+		WHERE watched_date >= ? AND watched_date < ?
+		GROUP BY month
+	`, from, to)
+	if err != nil {
+		return review, fmt.Errorf("failed to get viewings by month: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var month, count int
+		if err := rows.Scan(&month, &count); err != nil {
+			return review, fmt.Errorf("failed to scan month row: %w", err)
+		}
+		if month >= 1 && month <= 12 {
+			review.ByMonth[month-1].Count = count
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return review, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	review.HighestRated, err = m.selectMovies(`
+		SELECT `+movieColumns+`
+		FROM movies
+		WHERE rating > 0 AND letterboxd_id IN (`+inScope+`)
+		ORDER BY rating DESC, letterboxd_rating DESC
+		LIMIT 10
+	`, args...)
+	if err != nil {
+		return review, err
+	}
+
+	if review.TopDirectors, err = m.getTopPeople(RoleDirector, 10, inScope, args); err != nil {
+		return review, err
+	}
+	if review.TopActors, err = m.getTopPeople(RoleActor, 10, inScope, args); err != nil {
+		return review, err
+	}
+	if review.ByGenre, err = m.getTopTerms(genreTable, 20, inScope, args); err != nil {
+		return review, err
+	}
+
+	if review.ByDecade, err = m.countByDecade(inScope, args); err != nil {
+		return review, err
+	}
+
+	if review.Disagreements, err = m.getDisagreements(inScope, args, "ABS(rating - letterboxd_rating) DESC", 10); err != nil {
+		return review, err
+	}
+
+	review.Reviews, err = m.GetReviewsBetween(time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return review, err
+	}
+
+	return review, nil
+}
+
+// countByDecade counts the movies in scope by release decade, oldest first
+func (m *MovieDB) countByDecade(inScope string, args []interface{}) ([]DecadeCount, error) {
+	rows, err := m.db.Query(`
+		SELECT (year / 10) * 10 AS decade, COUNT(*)
+		FROM movies
+		WHERE year > 0 AND letterboxd_id IN (`+inScope+`)
+		GROUP BY decade
+		ORDER BY decade ASC
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count movies by decade: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []DecadeCount{}
+	for rows.Next() {
+		var count DecadeCount
+		if err := rows.Scan(&count.Decade, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan decade row: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return counts, nil
+}
@@ -1,22 +1,16 @@
 import { useState, useEffect } from 'react';
-import { GetAllMovies, SearchMovies, GetMoviesByRating, GetStats } from '../../wailsjs/go/main/App';
+import { QueryMovies, GetStats } from '../../wailsjs/go/main/App';
+import { database } from '../../wailsjs/go/models';
 import MovieList from './MovieList';
 import { Movie } from '../models';
 
-interface Stats {
-  total_movies?: number;
-  average_rating?: number;
-  total_runtime_formatted?: string;
-  [key: string]: any;
-}
-
 export default function Dashboard() {
   const [movies, setMovies] = useState<Movie[]>([]);
   const [loading, setLoading] = useState<boolean>(true);
   const [error, setError] = useState<string>('');
   const [searchQuery, setSearchQuery] = useState<string>('');
   const [filter, setFilter] = useState<string>('all');
-  const [stats, setStats] = useState<Stats>({});
+  const [stats, setStats] = useState<Partial<database.Stats>>({});
 
   useEffect(() => {
     loadMovies();
@@ -38,8 +32,8 @@ export default function Dashboard() {
   const loadMovies = async () => {
     try {
       setLoading(true);
-      const result = await GetAllMovies();
-      setMovies((result as Movie[]) || []);
+      const result = await QueryMovies(database.MovieQuery.createFrom({}));
+      setMovies((result.movies as Movie[]) || []);
       setError('');
     } catch (err) {
       setError('Failed to load movies: ' + (err instanceof Error ? err.message : String(err)));
@@ -51,8 +45,8 @@ export default function Dashboard() {
 
   const loadStats = async () => {
     try {
-      const result = await GetStats();
-      setStats((result as Stats) || {});
+      const result = await GetStats(database.StatsFilter.createFrom({}));
+      setStats(result || {});
     } catch (err) {
       console.error('Failed to load stats:', err);
     }
@@ -64,8 +58,8 @@ export default function Dashboard() {
       if (searchQuery.trim() === '') {
         loadMovies();
       } else {
-        const result = await SearchMovies(searchQuery);
-        setMovies((result as Movie[]) || []);
+        const result = await QueryMovies(database.MovieQuery.createFrom({ text: searchQuery, sort: 'title' }));
+        setMovies((result.movies as Movie[]) || []);
       }
       setError('');
     } catch (err) {
@@ -79,16 +73,18 @@ export default function Dashboard() {
     try {
       setLoading(true);
 
-      let result: Movie[] = [];
+      let query = database.MovieQuery.createFrom({});
       switch (filterType) {
         case 'highRated':
-          result = await GetMoviesByRating(4.0);
+          query = database.MovieQuery.createFrom({ rating_min: 4.0, sort: 'rating', descending: true });
+          break;
+        case 'liked':
+          query = database.MovieQuery.createFrom({ liked: true });
           break;
-        default:
-          result = await GetAllMovies();
       }
 
-      setMovies((result as Movie[]) || []);
+      const result = await QueryMovies(query);
+      setMovies((result.movies as Movie[]) || []);
       setError('');
     } catch (err) {
       setError('Filter failed: ' + (err instanceof Error ? err.message : String(err)));
@@ -141,6 +137,20 @@ export default function Dashboard() {
           >
             Highly Rated (★★★★+)
           </button>
+          <button
+            className={`px-5 py-2 rounded-md text-sm font-medium transition-all ${
+              filter === 'liked'
+                ? 'bg-letterboxd-green text-white'
+                : 'bg-[#456] text-letterboxd-light-gray hover:bg-[#567] hover:text-white'
+            }`}
+            onClick={() => {
+              setSearchQuery('');
+              setFilter('liked');
+              applyFilter('liked');
+            }}
+          >
+            Liked (♥)
+          </button>
         </div>
       </div>
 
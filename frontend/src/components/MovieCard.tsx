@@ -51,6 +51,7 @@ export default function MovieCard({ movie }: MovieCardProps) {
                   {formatRating(movie.rating)}
                 </div>
               )}
+              {movie.liked && <div className="text-letterboxd-orange text-sm">♥ Liked</div>}
             </div>
           </div>
         </div>
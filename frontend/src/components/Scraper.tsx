@@ -1,5 +1,44 @@
-import { useState } from 'react';
-import { ScrapeUserData } from '../../wailsjs/go/main/App';
+import { useState, useEffect } from 'react';
+import { ScrapeUserData, SyncUserData, CancelScrape, SelectExportFile, ImportLetterboxdExport } from '../../wailsjs/go/main/App';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
+
+interface ScrapeProgress {
+  phase: 'list' | 'details' | 'diary' | 'likes' | 'watchlist' | 'lists' | 'reviews' | 'done';
+  page: number;
+  current: number;
+  total: number;
+  scraped: number;
+  skipped: number;
+  failed: number;
+  title: string;
+}
+
+const describeProgress = (p: ScrapeProgress): string => {
+  switch (p.phase) {
+    case 'list':
+      return `Reading films list: page ${p.page} (${p.total} films found)`;
+    case 'details':
+      return `Film ${p.current}/${p.total}: ${p.title} — ${p.scraped} imported, ${p.skipped} skipped, ${p.failed} failed`;
+    case 'diary':
+      return `Reading diary: page ${p.page} (${p.total} entries found)`;
+    case 'likes':
+      return `Reading liked films: page ${p.page} (${p.total} found)`;
+    case 'watchlist':
+      return p.current > 0
+        ? `Watchlist film ${p.current}/${p.total}: ${p.title}`
+        : `Reading watchlist: page ${p.page} (${p.total} films found)`;
+    case 'lists':
+      return p.current > 0
+        ? `List ${p.current}/${p.total}: ${p.title}`
+        : `Reading lists: page ${p.page} (${p.total} lists found)`;
+    case 'reviews':
+      return p.current > 0
+        ? `Review ${p.current}/${p.total}: ${p.title}`
+        : `Reading reviews: page ${p.page} (${p.total} reviews found)`;
+    default:
+      return 'Finishing up...';
+  }
+};
 
 export default function Scraper() {
   const [username, setUsername] = useState('');
@@ -8,7 +47,38 @@ export default function Scraper() {
   const [error, setError] = useState('');
   const [success, setSuccess] = useState('');
 
-  const handleScrape = async () => {
+  useEffect(() => {
+    return EventsOn('scrape:progress', (p: ScrapeProgress) => setProgress(describeProgress(p)));
+  }, []);
+
+  const handleExportImport = async () => {
+    try {
+      const path = await SelectExportFile();
+      if (!path) {
+        return;
+      }
+
+      setScraping(true);
+      setError('');
+      setSuccess('');
+      setProgress('Reading Letterboxd export...');
+
+      const summary = await ImportLetterboxdExport(path);
+
+      setSuccess(
+        `Imported ${summary.films} new films, ${summary.ratings} ratings, ${summary.viewings} diary entries, ${summary.reviews} reviews, ${summary.liked} likes, ${summary.watchlist} watchlist films and ${summary.lists} lists` +
+          (summary.failed > 0 ? ` (${summary.failed} rows failed)` : '')
+      );
+      setProgress('');
+    } catch (err) {
+      setError(`Import failed: ${err instanceof Error ? err.message : String(err)}`);
+      setProgress('');
+    } finally {
+      setScraping(false);
+    }
+  };
+
+  const handleScrape = async (incremental = false) => {
     if (!username.trim()) {
       setError('Please enter a Letterboxd username');
       return;
@@ -20,7 +90,11 @@ export default function Scraper() {
       setSuccess('');
       setProgress('Starting import...');
 
-      await ScrapeUserData(username);
+      if (incremental) {
+        await SyncUserData(username);
+      } else {
+        await ScrapeUserData(username);
+      }
 
       setSuccess(`Successfully imported films from ${username}!`);
       setProgress('');
@@ -60,12 +134,42 @@ export default function Scraper() {
             className="flex-1 px-6 py-4 bg-letterboxd-dark border-2 border-[#456] rounded-lg text-white placeholder-[#678] focus:border-letterboxd-orange focus:outline-none text-lg disabled:opacity-50 disabled:cursor-not-allowed"
           />
           <button
-            onClick={handleScrape}
+            onClick={() => handleScrape()}
             disabled={scraping || !username.trim()}
             className="px-8 py-4 bg-letterboxd-orange hover:bg-[#ff9500] text-white font-bold rounded-lg transition-colors disabled:opacity-50 disabled:cursor-not-allowed disabled:hover:bg-letterboxd-orange"
           >
             {scraping ? 'Importing...' : 'Import'}
           </button>
+          <button
+            onClick={() => handleScrape(true)}
+            disabled={scraping || !username.trim()}
+            title="Only fetch films watched since your last import"
+            className="px-6 py-4 bg-[#456] hover:bg-[#567] text-white font-bold rounded-lg transition-colors disabled:opacity-50 disabled:cursor-not-allowed"
+          >
+            Quick sync
+          </button>
+          {scraping && (
+            <button
+              onClick={() => CancelScrape()}
+              className="px-6 py-4 bg-[#456] hover:bg-[#567] text-white font-bold rounded-lg transition-colors"
+            >
+              Cancel
+            </button>
+          )}
+        </div>
+
+        {/* Export ZIP */}
+        <div className="flex items-center justify-between gap-3 mb-8">
+          <p className="text-letterboxd-light-gray text-sm">
+            Private profile or flaky connection? Import the ZIP from Letterboxd's Settings → Data → Export instead.
+          </p>
+          <button
+            onClick={handleExportImport}
+            disabled={scraping}
+            className="px-6 py-3 bg-[#456] hover:bg-[#567] text-white font-bold rounded-lg transition-colors whitespace-nowrap disabled:opacity-50 disabled:cursor-not-allowed"
+          >
+            Import export ZIP
+          </button>
         </div>
 
         {/* Loading State */}
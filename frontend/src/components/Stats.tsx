@@ -1,42 +1,37 @@
 
 import { useState, useEffect } from 'react';
-import { GetStats } from '../../wailsjs/go/main/App';
-import { Movie } from '../models';
-
-interface PersonStat {
-  name: string;
-  movie_count: number;
-}
-
-interface StatsType {
-  total_movies?: number;
-  average_rating?: number;
-  average_letterboxd_rating?: number;
-  total_runtime_formatted?: string;
-  total_runtime_minutes?: number;
-  average_runtime_formatted?: string;
-  average_runtime_minutes?: number;
-  movies_by_year?: Record<string, number>;
-  top_movies?: Movie[];
-  top_directors?: PersonStat[];
-  top_actors?: PersonStat[];
-  top_writers?: PersonStat[];
-}
+import { GetStats, GetTagCounts } from '../../wailsjs/go/main/App';
+import { database } from '../../wailsjs/go/models';
 
 export default function Stats() {
-  const [stats, setStats] = useState<StatsType>({});
+  const [stats, setStats] = useState<Partial<database.Stats>>({});
+  const [year, setYear] = useState('');
+  const [decade, setDecade] = useState('');
+  const [tag, setTag] = useState('');
+  const [tags, setTags] = useState<database.TagStat[]>([]);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState('');
 
   useEffect(() => {
     loadStats();
+  }, [year, decade, tag]);
+
+  useEffect(() => {
+    GetTagCounts(database.StatsFilter.createFrom({}))
+      .then((result) => setTags(result || []))
+      .catch((err) => console.error('GetTagCounts error:', err));
   }, []);
 
   const loadStats = async () => {
     try {
       setLoading(true);
-      const result = await GetStats();
-      setStats((result as StatsType) || {});
+      const filter = database.StatsFilter.createFrom({
+        year: Number(year) || 0,
+        decade: Number(decade) || 0,
+        tag,
+      });
+      const result = await GetStats(filter);
+      setStats(result || {});
       setError('');
     } catch (err) {
       console.error('GetStats error:', err);
@@ -46,7 +41,8 @@ export default function Stats() {
     }
   };
 
-  if (loading) {
+  // Only the first load replaces the page, so the filter keeps focus while typing
+  if (loading && stats.total_movies === undefined) {
     return (
       <div className="flex items-center justify-center h-full">
         <div className="text-center">
@@ -65,11 +61,59 @@ export default function Stats() {
         </div>
       )}
 
+      {/* Filter */}
+      <div className="mb-6 flex items-center gap-3">
+        <label htmlFor="stats-year" className="text-letterboxd-light-gray text-sm font-medium">
+          Release year
+        </label>
+        <input
+          id="stats-year"
+          type="number"
+          placeholder="All"
+          value={year}
+          onChange={(e) => setYear(e.target.value)}
+          className="w-28 px-3 py-2 bg-[#2c3440] border border-[#456] rounded-md text-white placeholder-[#678] focus:border-letterboxd-orange focus:outline-none"
+        />
+        <label htmlFor="stats-decade" className="text-letterboxd-light-gray text-sm font-medium">
+          Decade
+        </label>
+        <input
+          id="stats-decade"
+          type="number"
+          step={10}
+          placeholder="All"
+          value={decade}
+          onChange={(e) => setDecade(e.target.value)}
+          className="w-28 px-3 py-2 bg-[#2c3440] border border-[#456] rounded-md text-white placeholder-[#678] focus:border-letterboxd-orange focus:outline-none"
+        />
+        {tags.length > 0 && (
+          <>
+            <label htmlFor="stats-tag" className="text-letterboxd-light-gray text-sm font-medium">
+              Tag
+            </label>
+            <select
+              id="stats-tag"
+              value={tag}
+              onChange={(e) => setTag(e.target.value)}
+              className="px-3 py-2 bg-[#2c3440] border border-[#456] rounded-md text-white focus:border-letterboxd-orange focus:outline-none"
+            >
+              <option value="">All</option>
+              {tags.map((t) => (
+                <option key={t.slug} value={t.slug}>
+                  {t.name} ({t.viewings})
+                </option>
+              ))}
+            </select>
+          </>
+        )}
+      </div>
+
       {/* Summary Statistics Cards */}
       <div className="grid grid-cols-1 md:grid-cols-2 lg:grid-cols-5 gap-2 mb-8">
         <div className="bg-[#1f2937] border border-[#456] rounded-lg p-6 shadow-lg">
           <h3 className="text-letterboxd-light-gray text-sm font-medium mb-2">Total Movies Watched</h3>
           <p className="text-4xl font-bold text-white">{stats.total_movies || 0}</p>
+          <p className="text-[#678] text-xs mt-1">{stats.total_liked || 0} liked</p>
         </div>
 
         <div className="bg-[#1f2937] border border-[#456] rounded-lg p-6 shadow-lg">
@@ -105,17 +149,70 @@ export default function Stats() {
         </div>
       </div>
 
+      {/* Rating Distribution */}
+      {stats.rating_distribution && stats.rating_distribution.rated > 0 && (
+        <div className="mb-8">
+          <h2 className="text-2xl font-bold text-white mb-6">Rating Distribution</h2>
+          <div className="bg-[#1f2937] border border-[#456] rounded-lg p-6 shadow-lg">
+            <div className="flex items-end gap-2 h-40">
+              {stats.rating_distribution.buckets.map(({ rating, count }) => {
+                const maxCount = Math.max(...stats.rating_distribution!.buckets.map((bucket) => bucket.count));
+                return (
+                  <div key={rating} className="flex-1 flex flex-col items-center justify-end h-full" title={`${count} × ${rating}`}>
+                    <div
+                      className="w-full bg-letterboxd-green rounded-t"
+                      style={{ height: `${maxCount > 0 ? (count / maxCount) * 100 : 0}%` }}
+                    ></div>
+                    <span className="text-[#678] text-xs mt-1">{rating}</span>
+                  </div>
+                );
+              })}
+            </div>
+            <p className="text-letterboxd-light-gray text-sm mt-4">
+              Median {stats.rating_distribution.median} · Mode {stats.rating_distribution.mode} · Std dev{' '}
+              {stats.rating_distribution.std_dev} · {stats.rating_distribution.unrated} unrated
+            </p>
+          </div>
+        </div>
+      )}
+
+      {/* Release Timeline */}
+      {stats.release_years && stats.release_years.length > 0 && (
+        <div className="mb-8">
+          <h2 className="text-2xl font-bold text-white mb-6">Release Timeline</h2>
+          <div className="bg-[#1f2937] border border-[#456] rounded-lg p-6 shadow-lg">
+            <div className="flex items-end gap-px h-40">
+              {stats.release_years.map((point) => {
+                const maxCount = Math.max(...stats.release_years!.map((p) => p.count));
+                return (
+                  <div
+                    key={point.year}
+                    className="flex-1 bg-letterboxd-orange rounded-t min-h-px"
+                    style={{ height: `${(point.count / maxCount) * 100}%` }}
+                    title={`${point.year}: ${point.count} films, avg ${point.average_rating.toFixed(2)} / Letterboxd ${point.average_letterboxd_rating.toFixed(2)}, ${point.total_runtime_minutes} min`}
+                  ></div>
+                );
+              })}
+            </div>
+            <div className="flex justify-between text-[#678] text-xs mt-2">
+              <span>{stats.release_years[0].year}</span>
+              <span>{stats.release_years[stats.release_years.length - 1].year}</span>
+            </div>
+          </div>
+        </div>
+      )}
+
       {/* Most Watched Years */}
-      {stats.movies_by_year && Object.keys(stats.movies_by_year).length > 0 && (
+      {stats.movies_by_year && stats.movies_by_year.length > 0 && (
         <div className="mb-8">
           <h2 className="text-2xl font-bold text-white mb-6">Most Watched Years</h2>
           <div className="bg-[#1f2937] border border-[#456] rounded-lg p-6 shadow-lg">
             <div className="space-y-4">
-              {Object.entries(stats.movies_by_year)
-                .sort(([yearA], [yearB]) => Number(yearB) - Number(yearA))
-                .map(([year, count]) => {
-                  const maxCount = Math.max(...Object.values(stats.movies_by_year!));
-                  const percentage = (Number(count) / maxCount) * 100;
+              {[...stats.movies_by_year]
+                .sort((a, b) => b.year - a.year)
+                .map(({ year, count }) => {
+                  const maxCount = Math.max(...stats.movies_by_year!.map((bucket) => bucket.count));
+                  const percentage = (count / maxCount) * 100;
                   return (
                     <div key={year} className="flex items-center gap-4">
                       <span className="text-white font-medium w-16">{year}</span>
@@ -125,12 +222,12 @@ export default function Stats() {
                           style={{ width: `${percentage}%` }}
                         >
                           {percentage > 20 && (
-                            <span className="text-white text-sm font-medium">{count as number}</span>
+                            <span className="text-white text-sm font-medium">{count}</span>
                           )}
                         </div>
                       </div>
                       {percentage <= 20 && (
-                        <span className="text-letterboxd-light-gray font-medium w-8 text-right">{count as number}</span>
+                        <span className="text-letterboxd-light-gray font-medium w-8 text-right">{count}</span>
                       )}
                     </div>
                   );
@@ -148,7 +245,7 @@ export default function Stats() {
             <div className="space-y-0">
               {stats.top_movies.slice(0, 10).map((movie, idx) => (
                 <div
-                  key={movie.id}
+                  key={movie.letterboxd_id}
                   className="px-6 py-4 border-b border-[#456] last:border-b-0 hover:bg-[#2a3548] transition-colors flex items-center justify-between"
                 >
                   <div className="flex items-center gap-4 flex-1">
@@ -225,7 +322,7 @@ export default function Stats() {
 
       {/* Top Writers */}
       {stats.top_writers && stats.top_writers.length > 0 && (
-        <div className="mt-8 mb-8">
+        <div className="mt-8">
           <h2 className="text-2xl font-bold text-white mb-6">Top Writers</h2>
           <div className="bg-[#1f2937] border border-[#456] rounded-lg overflow-hidden shadow-lg">
             <div className="space-y-0">
@@ -247,6 +344,32 @@ export default function Stats() {
           </div>
         </div>
       )}
+
+      {/* Genres, countries and languages */}
+      <div className="mt-8 mb-8 grid grid-cols-1 md:grid-cols-3 gap-6">
+        {[
+          { title: 'Top Genres', items: stats.top_genres },
+          { title: 'Top Countries', items: stats.top_countries },
+          { title: 'Top Languages', items: stats.top_languages },
+        ].map(({ title, items }) =>
+          items && items.length > 0 ? (
+            <div key={title}>
+              <h2 className="text-2xl font-bold text-white mb-6">{title}</h2>
+              <div className="bg-[#1f2937] border border-[#456] rounded-lg overflow-hidden shadow-lg">
+                {items.map((item, idx) => (
+                  <div
+                    key={idx}
+                    className="px-6 py-3 border-b border-[#456] last:border-b-0 hover:bg-[#2a3548] transition-colors flex items-center justify-between"
+                  >
+                    <span className="text-white font-medium">{item.name}</span>
+                    <span className="text-letterboxd-blue font-medium">{item.movie_count}</span>
+                  </div>
+                ))}
+              </div>
+            </div>
+          ) : null
+        )}
+      </div>
     </div>
   );
 }
@@ -11,4 +11,5 @@ export interface Movie {
   director?: string;
   cast?: string;
   writers?: string;
+  liked?: boolean;
 }
\ No newline at end of file
@@ -1,17 +1,53 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {database} from '../models';
+import {importer} from '../models';
+import {scraper} from '../models';
+
+export function CancelScrape():Promise<void>;
 
 export function DeleteDatabase():Promise<void>;
 
-export function GetAllMovies():Promise<Array<database.Movie>>;
+export function DiffList(arg1:string):Promise<database.ListDiff>;
+
+export function ExportYearInReview(arg1:number):Promise<string>;
+
+export function GetDisagreementStats(arg1:database.StatsFilter):Promise<database.DisagreementStats>;
+
+export function GetListEntries(arg1:string):Promise<Array<database.ListEntry>>;
+
+export function GetLists():Promise<Array<database.List>>;
+
+export function GetRatingHistory(arg1:string):Promise<Array<database.RatingChange>>;
+
+export function GetReratedFilms(arg1:string,arg2:string):Promise<Array<database.RatingChange>>;
+
+export function GetReviews(arg1:string):Promise<Array<database.Review>>;
+
+export function GetStats(arg1:database.StatsFilter):Promise<database.Stats>;
 
-export function GetMoviesByRating(arg1:number):Promise<Array<database.Movie>>;
+export function GetSyncState(arg1:string):Promise<database.SyncState>;
 
-export function GetMoviesByYear(arg1:number):Promise<Array<database.Movie>>;
+export function GetTagCooccurrence(arg1:string,arg2:number):Promise<Array<database.TagPair>>;
 
-export function GetStats():Promise<Record<string, any>>;
+export function GetTagCounts(arg1:database.StatsFilter):Promise<Array<database.TagStat>>;
+
+export function GetViewings(arg1:string):Promise<Array<database.Viewing>>;
+
+export function GetYearInReview(arg1:number):Promise<database.YearInReview>;
+
+export function ImportLetterboxdExport(arg1:string):Promise<importer.Summary>;
+
+export function QueryMovies(arg1:database.MovieQuery):Promise<database.MovieQueryResult>;
+
+export function QueryWatchlist(arg1:database.WatchlistQuery):Promise<database.WatchlistQueryResult>;
+
+export function RefreshUserData(arg1:string,arg2:number):Promise<scraper.Result>;
 
 export function ScrapeUserData(arg1:string):Promise<void>;
 
-export function SearchMovies(arg1:string):Promise<Array<database.Movie>>;
+export function SearchFullText(arg1:string,arg2:number):Promise<Array<database.SearchResult>>;
+
+export function SelectExportFile():Promise<string>;
+
+export function SyncUserData(arg1:string):Promise<void>;
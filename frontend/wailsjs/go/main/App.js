@@ -2,30 +2,98 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function CancelScrape() {
+  return window['go']['main']['App']['CancelScrape']();
+}
+
 export function DeleteDatabase() {
   return window['go']['main']['App']['DeleteDatabase']();
 }
 
-export function GetAllMovies() {
-  return window['go']['main']['App']['GetAllMovies']();
+export function DiffList(arg1) {
+  return window['go']['main']['App']['DiffList'](arg1);
+}
+
+export function ExportYearInReview(arg1) {
+  return window['go']['main']['App']['ExportYearInReview'](arg1);
+}
+
+export function GetDisagreementStats(arg1) {
+  return window['go']['main']['App']['GetDisagreementStats'](arg1);
+}
+
+export function GetListEntries(arg1) {
+  return window['go']['main']['App']['GetListEntries'](arg1);
+}
+
+export function GetLists() {
+  return window['go']['main']['App']['GetLists']();
+}
+
+export function GetRatingHistory(arg1) {
+  return window['go']['main']['App']['GetRatingHistory'](arg1);
+}
+
+export function GetReratedFilms(arg1,arg2) {
+  return window['go']['main']['App']['GetReratedFilms'](arg1,arg2);
+}
+
+export function GetReviews(arg1) {
+  return window['go']['main']['App']['GetReviews'](arg1);
+}
+
+export function GetStats(arg1) {
+  return window['go']['main']['App']['GetStats'](arg1);
+}
+
+export function GetSyncState(arg1) {
+  return window['go']['main']['App']['GetSyncState'](arg1);
+}
+
+export function GetTagCooccurrence(arg1,arg2) {
+  return window['go']['main']['App']['GetTagCooccurrence'](arg1,arg2);
+}
+
+export function GetTagCounts(arg1) {
+  return window['go']['main']['App']['GetTagCounts'](arg1);
+}
+
+export function GetViewings(arg1) {
+  return window['go']['main']['App']['GetViewings'](arg1);
 }
 
-export function GetMoviesByRating(arg1) {
-  return window['go']['main']['App']['GetMoviesByRating'](arg1);
+export function GetYearInReview(arg1) {
+  return window['go']['main']['App']['GetYearInReview'](arg1);
 }
 
-export function GetMoviesByYear(arg1) {
-  return window['go']['main']['App']['GetMoviesByYear'](arg1);
+export function ImportLetterboxdExport(arg1) {
+  return window['go']['main']['App']['ImportLetterboxdExport'](arg1);
 }
 
-export function GetStats() {
-  return window['go']['main']['App']['GetStats']();
+export function QueryMovies(arg1) {
+  return window['go']['main']['App']['QueryMovies'](arg1);
+}
+
+export function QueryWatchlist(arg1) {
+  return window['go']['main']['App']['QueryWatchlist'](arg1);
+}
+
+export function RefreshUserData(arg1,arg2) {
+  return window['go']['main']['App']['RefreshUserData'](arg1,arg2);
 }
 
 export function ScrapeUserData(arg1) {
   return window['go']['main']['App']['ScrapeUserData'](arg1);
 }
 
-export function SearchMovies(arg1) {
-  return window['go']['main']['App']['SearchMovies'](arg1);
+export function SearchFullText(arg1,arg2) {
+  return window['go']['main']['App']['SearchFullText'](arg1,arg2);
+}
+
+export function SelectExportFile() {
+  return window['go']['main']['App']['SelectExportFile']();
+}
+
+export function SyncUserData(arg1) {
+  return window['go']['main']['App']['SyncUserData'](arg1);
 }
@@ -1,5 +1,308 @@
 export namespace database {
 	
+	export class Credit {
+	    person_slug: string;
+	    name: string;
+	    role: string;
+	    billing_order: number;
+	    character_name: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Credit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.person_slug = source["person_slug"];
+	        this.name = source["name"];
+	        this.role = source["role"];
+	        this.billing_order = source["billing_order"];
+	        this.character_name = source["character_name"];
+	    }
+	}
+	export class DecadeCount {
+	    decade: number;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DecadeCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.decade = source["decade"];
+	        this.count = source["count"];
+	    }
+	}
+	export class Disagreement {
+	    movie: Movie;
+	    difference: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Disagreement(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.movie = this.convertValues(source["movie"], Movie);
+	        this.difference = source["difference"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DisagreementStats {
+	    rated_films: number;
+	    mean_deviation: number;
+	    mean_absolute_deviation: number;
+	    correlation: number;
+	    above_crowd: Disagreement[];
+	    below_crowd: Disagreement[];
+	    by_director: GroupBias[];
+	    by_decade: GroupBias[];
+	    by_genre: GroupBias[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DisagreementStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rated_films = source["rated_films"];
+	        this.mean_deviation = source["mean_deviation"];
+	        this.mean_absolute_deviation = source["mean_absolute_deviation"];
+	        this.correlation = source["correlation"];
+	        this.above_crowd = this.convertValues(source["above_crowd"], Disagreement);
+	        this.below_crowd = this.convertValues(source["below_crowd"], Disagreement);
+	        this.by_director = this.convertValues(source["by_director"], GroupBias);
+	        this.by_decade = this.convertValues(source["by_decade"], GroupBias);
+	        this.by_genre = this.convertValues(source["by_genre"], GroupBias);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FieldChange {
+	    letterboxd_id: string;
+	    title: string;
+	    field: string;
+	    old: string;
+	    new: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FieldChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.letterboxd_id = source["letterboxd_id"];
+	        this.title = source["title"];
+	        this.field = source["field"];
+	        this.old = source["old"];
+	        this.new = source["new"];
+	    }
+	}
+	export class GroupBias {
+	    key: string;
+	    name: string;
+	    films: number;
+	    mean_deviation: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GroupBias(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.name = source["name"];
+	        this.films = source["films"];
+	        this.mean_deviation = source["mean_deviation"];
+	    }
+	}
+	export class List {
+	    slug: string;
+	    name: string;
+	    description: string;
+	    url: string;
+	    ranked: boolean;
+	    film_count: number;
+	    // Go type: time
+	    updated_at: any;
+	    // Go type: time
+	    synced_at: any;
+	    // Go type: time
+	    previous_synced_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new List(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.slug = source["slug"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.url = source["url"];
+	        this.ranked = source["ranked"];
+	        this.film_count = source["film_count"];
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	        this.synced_at = this.convertValues(source["synced_at"], null);
+	        this.previous_synced_at = this.convertValues(source["previous_synced_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ListDiff {
+	    list: List;
+	    added: ListEntry[];
+	    removed: ListEntry[];
+	    moved: ListMove[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ListDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.list = this.convertValues(source["list"], List);
+	        this.added = this.convertValues(source["added"], ListEntry);
+	        this.removed = this.convertValues(source["removed"], ListEntry);
+	        this.moved = this.convertValues(source["moved"], ListMove);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ListEntry {
+	    position: number;
+	    letterboxd_id: string;
+	    title: string;
+	    letterboxd_url: string;
+	    watched: boolean;
+	    rating: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ListEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.position = source["position"];
+	        this.letterboxd_id = source["letterboxd_id"];
+	        this.title = source["title"];
+	        this.letterboxd_url = source["letterboxd_url"];
+	        this.watched = source["watched"];
+	        this.rating = source["rating"];
+	    }
+	}
+	export class ListMove {
+	    entry: ListEntry;
+	    old_position: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ListMove(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entry = this.convertValues(source["entry"], ListEntry);
+	        this.old_position = source["old_position"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MonthCount {
+	    month: number;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MonthCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.month = source["month"];
+	        this.count = source["count"];
+	    }
+	}
 	export class Movie {
 	    letterboxd_id: string;
 	    title: string;
@@ -14,6 +317,21 @@ export namespace database {
 	    director: string;
 	    cast: string;
 	    writers: string;
+	    letterboxd_uri: string;
+	    // Go type: time
+	    details_scraped_at: any;
+	    tmdb_id: number;
+	    tmdb_type: string;
+	    imdb_id: string;
+	    liked: boolean;
+	    synopsis: string;
+	    credits?: Credit[];
+	    genres?: Term[];
+	    countries?: Term[];
+	    primary_language?: Term;
+	    languages?: Term[];
+	    studios?: Term[];
+	    themes?: Term[];
 	
 	    static createFrom(source: any = {}) {
 	        return new Movie(source);
@@ -33,6 +351,865 @@ export namespace database {
 	        this.director = source["director"];
 	        this.cast = source["cast"];
 	        this.writers = source["writers"];
+	        this.letterboxd_uri = source["letterboxd_uri"];
+	        this.details_scraped_at = this.convertValues(source["details_scraped_at"], null);
+	        this.tmdb_id = source["tmdb_id"];
+	        this.tmdb_type = source["tmdb_type"];
+	        this.imdb_id = source["imdb_id"];
+	        this.liked = source["liked"];
+	        this.synopsis = source["synopsis"];
+	        this.credits = this.convertValues(source["credits"], Credit);
+	        this.genres = this.convertValues(source["genres"], Term);
+	        this.countries = this.convertValues(source["countries"], Term);
+	        this.primary_language = this.convertValues(source["primary_language"], Term);
+	        this.languages = this.convertValues(source["languages"], Term);
+	        this.studios = this.convertValues(source["studios"], Term);
+	        this.themes = this.convertValues(source["themes"], Term);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MovieQuery {
+	    text: string;
+	    year_min: number;
+	    year_max: number;
+	    rating_min: number;
+	    rating_max: number;
+	    letterboxd_rating_min: number;
+	    letterboxd_rating_max: number;
+	    length_min: number;
+	    length_max: number;
+	    added_from: string;
+	    added_to: string;
+	    liked: boolean;
+	    people: PersonFilter[];
+	    genres: string[];
+	    tags: string[];
+	    sort: string;
+	    descending: boolean;
+	    limit: number;
+	    offset: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MovieQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.year_min = source["year_min"];
+	        this.year_max = source["year_max"];
+	        this.rating_min = source["rating_min"];
+	        this.rating_max = source["rating_max"];
+	        this.letterboxd_rating_min = source["letterboxd_rating_min"];
+	        this.letterboxd_rating_max = source["letterboxd_rating_max"];
+	        this.length_min = source["length_min"];
+	        this.length_max = source["length_max"];
+	        this.added_from = source["added_from"];
+	        this.added_to = source["added_to"];
+	        this.liked = source["liked"];
+	        this.people = this.convertValues(source["people"], PersonFilter);
+	        this.genres = source["genres"];
+	        this.tags = source["tags"];
+	        this.sort = source["sort"];
+	        this.descending = source["descending"];
+	        this.limit = source["limit"];
+	        this.offset = source["offset"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MovieQueryResult {
+	    movies: Movie[];
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MovieQueryResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.movies = this.convertValues(source["movies"], Movie);
+	        this.total = source["total"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PersonFilter {
+	    slug: string;
+	    role: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PersonFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.slug = source["slug"];
+	        this.role = source["role"];
+	    }
+	}
+	export class PersonStat {
+	    slug: string;
+	    name: string;
+	    movie_count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PersonStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.slug = source["slug"];
+	        this.name = source["name"];
+	        this.movie_count = source["movie_count"];
+	    }
+	}
+	export class RatingBucket {
+	    rating: number;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RatingBucket(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rating = source["rating"];
+	        this.count = source["count"];
+	    }
+	}
+	export class RatingChange {
+	    id: number;
+	    letterboxd_id: string;
+	    title: string;
+	    field: string;
+	    old_value: number;
+	    new_value: number;
+	    // Go type: time
+	    observed_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new RatingChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.letterboxd_id = source["letterboxd_id"];
+	        this.title = source["title"];
+	        this.field = source["field"];
+	        this.old_value = source["old_value"];
+	        this.new_value = source["new_value"];
+	        this.observed_at = this.convertValues(source["observed_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RatingDistribution {
+	    buckets: RatingBucket[];
+	    rated: number;
+	    unrated: number;
+	    median: number;
+	    mode: number;
+	    std_dev: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RatingDistribution(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.buckets = this.convertValues(source["buckets"], RatingBucket);
+	        this.rated = source["rated"];
+	        this.unrated = source["unrated"];
+	        this.median = source["median"];
+	        this.mode = source["mode"];
+	        this.std_dev = source["std_dev"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Review {
+	    key: string;
+	    letterboxd_id: string;
+	    title: string;
+	    url: string;
+	    // Go type: time
+	    watched_date: any;
+	    rating: number;
+	    text: string;
+	    like_count: number;
+	    spoiler: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Review(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.letterboxd_id = source["letterboxd_id"];
+	        this.title = source["title"];
+	        this.url = source["url"];
+	        this.watched_date = this.convertValues(source["watched_date"], null);
+	        this.rating = source["rating"];
+	        this.text = source["text"];
+	        this.like_count = source["like_count"];
+	        this.spoiler = source["spoiler"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SearchResult {
+	    movie: Movie;
+	    title_html: string;
+	    snippet: string;
+	    score: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.movie = this.convertValues(source["movie"], Movie);
+	        this.title_html = source["title_html"];
+	        this.snippet = source["snippet"];
+	        this.score = source["score"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Stats {
+	    total_movies: number;
+	    average_rating: number;
+	    average_letterboxd_rating: number;
+	    total_runtime_minutes: number;
+	    total_runtime_formatted: string;
+	    average_runtime_minutes: number;
+	    average_runtime_formatted: string;
+	    total_viewings: number;
+	    total_rewatches: number;
+	    total_liked: number;
+	    viewings_by_year: YearCount[];
+	    movies_by_year: YearCount[];
+	    top_movies: Movie[];
+	    top_directors: PersonStat[];
+	    top_actors: PersonStat[];
+	    top_writers: PersonStat[];
+	    top_genres: TermStat[];
+	    top_countries: TermStat[];
+	    top_languages: TermStat[];
+	    release_years: TimelinePoint[];
+	    release_decades: TimelinePoint[];
+	    rating_distribution: RatingDistribution;
+	    letterboxd_rating_distribution: RatingDistribution;
+	
+	    static createFrom(source: any = {}) {
+	        return new Stats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.total_movies = source["total_movies"];
+	        this.average_rating = source["average_rating"];
+	        this.average_letterboxd_rating = source["average_letterboxd_rating"];
+	        this.total_runtime_minutes = source["total_runtime_minutes"];
+	        this.total_runtime_formatted = source["total_runtime_formatted"];
+	        this.average_runtime_minutes = source["average_runtime_minutes"];
+	        this.average_runtime_formatted = source["average_runtime_formatted"];
+	        this.total_viewings = source["total_viewings"];
+	        this.total_rewatches = source["total_rewatches"];
+	        this.total_liked = source["total_liked"];
+	        this.viewings_by_year = this.convertValues(source["viewings_by_year"], YearCount);
+	        this.movies_by_year = this.convertValues(source["movies_by_year"], YearCount);
+	        this.top_movies = this.convertValues(source["top_movies"], Movie);
+	        this.top_directors = this.convertValues(source["top_directors"], PersonStat);
+	        this.top_actors = this.convertValues(source["top_actors"], PersonStat);
+	        this.top_writers = this.convertValues(source["top_writers"], PersonStat);
+	        this.top_genres = this.convertValues(source["top_genres"], TermStat);
+	        this.top_countries = this.convertValues(source["top_countries"], TermStat);
+	        this.top_languages = this.convertValues(source["top_languages"], TermStat);
+	        this.release_years = this.convertValues(source["release_years"], TimelinePoint);
+	        this.release_decades = this.convertValues(source["release_decades"], TimelinePoint);
+	        this.rating_distribution = this.convertValues(source["rating_distribution"], RatingDistribution);
+	        this.letterboxd_rating_distribution = this.convertValues(source["letterboxd_rating_distribution"], RatingDistribution);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StatsFilter {
+	    year: number;
+	    decade: number;
+	    rating_min: number;
+	    rating_max: number;
+	    genre: string;
+	    liked: boolean;
+	    tag: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StatsFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.year = source["year"];
+	        this.decade = source["decade"];
+	        this.rating_min = source["rating_min"];
+	        this.rating_max = source["rating_max"];
+	        this.genre = source["genre"];
+	        this.liked = source["liked"];
+	        this.tag = source["tag"];
+	    }
+	}
+	export class SyncState {
+	    username: string;
+	    // Go type: time
+	    last_synced_at: any;
+	    // Go type: time
+	    last_full_sync_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new SyncState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.username = source["username"];
+	        this.last_synced_at = this.convertValues(source["last_synced_at"], null);
+	        this.last_full_sync_at = this.convertValues(source["last_full_sync_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TagPair {
+	    tag: Term;
+	    other: Term;
+	    viewings: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TagPair(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tag = this.convertValues(source["tag"], Term);
+	        this.other = this.convertValues(source["other"], Term);
+	        this.viewings = source["viewings"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TagStat {
+	    slug: string;
+	    name: string;
+	    viewings: number;
+	    films: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TagStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.slug = source["slug"];
+	        this.name = source["name"];
+	        this.viewings = source["viewings"];
+	        this.films = source["films"];
+	    }
+	}
+	export class Term {
+	    slug: string;
+	    name: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Term(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.slug = source["slug"];
+	        this.name = source["name"];
+	    }
+	}
+	export class TermStat {
+	    slug: string;
+	    name: string;
+	    movie_count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TermStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.slug = source["slug"];
+	        this.name = source["name"];
+	        this.movie_count = source["movie_count"];
+	    }
+	}
+	export class TimelinePoint {
+	    year: number;
+	    count: number;
+	    average_rating: number;
+	    average_letterboxd_rating: number;
+	    total_runtime_minutes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TimelinePoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.year = source["year"];
+	        this.count = source["count"];
+	        this.average_rating = source["average_rating"];
+	        this.average_letterboxd_rating = source["average_letterboxd_rating"];
+	        this.total_runtime_minutes = source["total_runtime_minutes"];
+	    }
+	}
+	export class Viewing {
+	    id: number;
+	    letterboxd_viewing_id: string;
+	    letterboxd_id: string;
+	    // Go type: time
+	    watched_date: any;
+	    rewatch: boolean;
+	    rating: number;
+	    review_url: string;
+	    tags: Term[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Viewing(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.letterboxd_viewing_id = source["letterboxd_viewing_id"];
+	        this.letterboxd_id = source["letterboxd_id"];
+	        this.watched_date = this.convertValues(source["watched_date"], null);
+	        this.rewatch = source["rewatch"];
+	        this.rating = source["rating"];
+	        this.review_url = source["review_url"];
+	        this.tags = this.convertValues(source["tags"], Term);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WatchlistEntry {
+	    movie: Movie;
+	    position: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchlistEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.movie = this.convertValues(source["movie"], Movie);
+	        this.position = source["position"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WatchlistQuery {
+	    text: string;
+	    year_min: number;
+	    year_max: number;
+	    letterboxd_rating_min: number;
+	    letterboxd_rating_max: number;
+	    length_min: number;
+	    length_max: number;
+	    added_from: string;
+	    added_to: string;
+	    sort: string;
+	    descending: boolean;
+	    limit: number;
+	    offset: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchlistQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.year_min = source["year_min"];
+	        this.year_max = source["year_max"];
+	        this.letterboxd_rating_min = source["letterboxd_rating_min"];
+	        this.letterboxd_rating_max = source["letterboxd_rating_max"];
+	        this.length_min = source["length_min"];
+	        this.length_max = source["length_max"];
+	        this.added_from = source["added_from"];
+	        this.added_to = source["added_to"];
+	        this.sort = source["sort"];
+	        this.descending = source["descending"];
+	        this.limit = source["limit"];
+	        this.offset = source["offset"];
+	    }
+	}
+	export class WatchlistQueryResult {
+	    entries: WatchlistEntry[];
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchlistQueryResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entries = this.convertValues(source["entries"], WatchlistEntry);
+	        this.total = source["total"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class YearCount {
+	    year: number;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new YearCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.year = source["year"];
+	        this.count = source["count"];
+	    }
+	}
+	export class YearInReview {
+	    year: number;
+	    films_watched: number;
+	    viewings: number;
+	    rewatches: number;
+	    total_minutes: number;
+	    hours: number;
+	    highest_rated: Movie[];
+	    top_directors: PersonStat[];
+	    top_actors: PersonStat[];
+	    by_month: MonthCount[];
+	    by_decade: DecadeCount[];
+	    by_genre: TermStat[];
+	    disagreements: Disagreement[];
+	    reviews: Review[];
+	
+	    static createFrom(source: any = {}) {
+	        return new YearInReview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.year = source["year"];
+	        this.films_watched = source["films_watched"];
+	        this.viewings = source["viewings"];
+	        this.rewatches = source["rewatches"];
+	        this.total_minutes = source["total_minutes"];
+	        this.hours = source["hours"];
+	        this.highest_rated = this.convertValues(source["highest_rated"], Movie);
+	        this.top_directors = this.convertValues(source["top_directors"], PersonStat);
+	        this.top_actors = this.convertValues(source["top_actors"], PersonStat);
+	        this.by_month = this.convertValues(source["by_month"], MonthCount);
+	        this.by_decade = this.convertValues(source["by_decade"], DecadeCount);
+	        this.by_genre = this.convertValues(source["by_genre"], TermStat);
+	        this.disagreements = this.convertValues(source["disagreements"], Disagreement);
+	        this.reviews = this.convertValues(source["reviews"], Review);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace importer {
+	
+	export class Summary {
+	    films: number;
+	    ratings: number;
+	    viewings: number;
+	    reviews: number;
+	    liked: number;
+	    watchlist: number;
+	    lists: number;
+	    skipped: number;
+	    failed: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Summary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.films = source["films"];
+	        this.ratings = source["ratings"];
+	        this.viewings = source["viewings"];
+	        this.reviews = source["reviews"];
+	        this.liked = source["liked"];
+	        this.watchlist = source["watchlist"];
+	        this.lists = source["lists"];
+	        this.skipped = source["skipped"];
+	        this.failed = source["failed"];
+	    }
+	}
+
+}
+
+export namespace scraper {
+	
+	export class Result {
+	    scraped: number;
+	    refreshed: number;
+	    skipped: number;
+	    failed: number;
+	    viewings: number;
+	    liked: number;
+	    watchlist: number;
+	    lists: number;
+	    reviews: number;
+	    changes: database.FieldChange[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.scraped = source["scraped"];
+	        this.refreshed = source["refreshed"];
+	        this.skipped = source["skipped"];
+	        this.failed = source["failed"];
+	        this.viewings = source["viewings"];
+	        this.liked = source["liked"];
+	        this.watchlist = source["watchlist"];
+	        this.lists = source["lists"];
+	        this.reviews = source["reviews"];
+	        this.changes = this.convertValues(source["changes"], database.FieldChange);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
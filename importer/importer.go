@@ -0,0 +1,571 @@
+package importer
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"letterboxd-tracker/database"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exportDateLayout is the date format used throughout the export CSVs
+const exportDateLayout = "2006-01-02"
+
+// Summary reports what an export import did
+type Summary struct {
+	Films     int `json:"films"`
+	Ratings   int `json:"ratings"`
+	Viewings  int `json:"viewings"`
+	Reviews   int `json:"reviews"`
+	Liked     int `json:"liked"`
+	Watchlist int `json:"watchlist"`
+	Lists     int `json:"lists"`
+	Skipped   int `json:"skipped"`
+	Failed    int `json:"failed"`
+}
+
+// Importer loads the official Letterboxd data export ZIP into the database.
+// It works entirely offline, so it also covers private profiles
+type Importer struct {
+	db *database.MovieDB
+}
+
+// NewImporter creates a new Importer instance
+func NewImporter(db *database.MovieDB) *Importer {
+	return &Importer{db: db}
+}
+
+// ImportZip reads the export at path and upserts its films, ratings,
+// diary entries, reviews, likes, watchlist and lists. Files are applied in
+// dependency order: watched.csv creates films, the rest attach data to them
+func (i *Importer) ImportZip(path string) (Summary, error) {
+	var summary Summary
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return summary, fmt.Errorf("failed to open export: %w", err)
+	}
+	defer zr.Close()
+
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	if files["watched.csv"] == nil {
+		return summary, fmt.Errorf("not a Letterboxd export: watched.csv is missing")
+	}
+
+	steps := []struct {
+		name  string
+		apply func([]record, *Summary)
+	}{
+		{"watched.csv", i.importWatched},
+		{"ratings.csv", i.importRatings},
+		{"diary.csv", i.importDiary},
+		{"reviews.csv", i.importReviews},
+		{"likes/films.csv", i.importLikes},
+		{"watchlist.csv", i.importWatchlist},
+	}
+
+	for _, step := range steps {
+		f := files[step.name]
+		if f == nil {
+			log.Printf("Export has no %s, skipping\n", step.name)
+			continue
+		}
+
+		records, err := readCSV(f)
+		if err != nil {
+			return summary, fmt.Errorf("failed to read %s: %w", step.name, err)
+		}
+
+		log.Printf("Importing %d rows from %s\n", len(records), step.name)
+		step.apply(records, &summary)
+	}
+
+	for _, f := range zr.File {
+		if strings.HasPrefix(f.Name, "lists/") && strings.HasSuffix(f.Name, ".csv") {
+			i.importList(f, &summary)
+		}
+	}
+
+	log.Printf("Export import complete: %d films, %d ratings, %d viewings, %d reviews, %d liked, %d watchlist, %d lists, %d skipped, %d failed\n",
+		summary.Films, summary.Ratings, summary.Viewings, summary.Reviews, summary.Liked, summary.Watchlist,
+		summary.Lists, summary.Skipped, summary.Failed)
+
+	return summary, nil
+}
+
+// importWatched creates a movie for every watched film not already stored
+func (i *Importer) importWatched(records []record, summary *Summary) {
+	for _, r := range records {
+		if _, err := i.ensureMovie(r, summary); err != nil {
+			log.Printf("Error importing %s: %v\n", r.get("Name"), err)
+			summary.Failed++
+		}
+	}
+}
+
+// importRatings sets the personal rating of each rated film
+func (i *Importer) importRatings(records []record, summary *Summary) {
+	for _, r := range records {
+		id, err := i.ensureMovie(r, summary)
+		if err != nil {
+			log.Printf("Error importing rating for %s: %v\n", r.get("Name"), err)
+			summary.Failed++
+			continue
+		}
+
+		rating := r.float("Rating")
+		if rating <= 0 {
+			summary.Skipped++
+			continue
+		}
+
+		if err := i.db.SetRating(id, rating); err != nil {
+			log.Printf("Error importing rating for %s: %v\n", r.get("Name"), err)
+			summary.Failed++
+			continue
+		}
+		summary.Ratings++
+	}
+}
+
+// importDiary records one viewing per diary entry
+func (i *Importer) importDiary(records []record, summary *Summary) {
+	for _, r := range records {
+		if _, err := i.importViewing(r, "", summary); err != nil {
+			log.Printf("Error importing diary entry for %s: %v\n", r.get("Name"), err)
+			summary.Failed++
+			continue
+		}
+		summary.Viewings++
+	}
+}
+
+// importReviews attaches review links to the matching viewings and stores
+// the review text. Reviews without a watched date have no viewing.
+// Reviews already scraped are left alone, since the scraper also records
+// like counts and spoiler flags that the export lacks
+func (i *Importer) importReviews(records []record, summary *Summary) {
+	for _, r := range records {
+		uri := r.get("Letterboxd URI")
+		watched, dated := r.date("Watched Date")
+
+		var id string
+		var err error
+		if dated {
+			id, err = i.importViewing(r, uri, summary)
+		} else {
+			id, err = i.ensureMovie(r.withoutURI(), summary)
+		}
+		if err != nil {
+			log.Printf("Error importing review for %s: %v\n", r.get("Name"), err)
+			summary.Failed++
+			continue
+		}
+
+		if text := r.get("Review"); text != "" {
+			key := database.ReviewKey(id, watched)
+
+			exists, err := i.db.ReviewExists(key)
+			if err == nil && !exists {
+				err = i.db.SaveReview(database.Review{
+					Key:          key,
+					LetterboxdID: id,
+					URL:          uri,
+					WatchedDate:  watched,
+					Rating:       r.float("Rating"),
+					Text:         text,
+				})
+			}
+			if err != nil {
+				log.Printf("Error importing review text for %s: %v\n", r.get("Name"), err)
+				summary.Failed++
+				continue
+			}
+		}
+		summary.Reviews++
+	}
+}
+
+// importLikes marks exactly the liked films that are in the collection as
+// liked. Liked films that were never watched have no movie and are skipped
+func (i *Importer) importLikes(records []record, summary *Summary) {
+	var ids []string
+	for _, r := range records {
+		id, found, err := i.db.ResolveMovieID(r.get("Letterboxd URI"), r.get("Name"), r.int("Year"))
+		if err != nil {
+			log.Printf("Error importing like of %s: %v\n", r.get("Name"), err)
+			summary.Failed++
+			continue
+		}
+		if !found {
+			summary.Skipped++
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	if _, err := i.db.SetLikedFilms(ids); err != nil {
+		log.Printf("Error importing likes: %v\n", err)
+		summary.Failed++
+		return
+	}
+	summary.Liked += len(ids)
+}
+
+// importWatchlist stores the watchlist in file order. A watchlist that was
+// already scraped is kept, since it is more recent and has film details
+func (i *Importer) importWatchlist(records []record, summary *Summary) {
+	stored, err := i.db.QueryWatchlist(database.WatchlistQuery{Limit: 1})
+	if err != nil {
+		log.Printf("Error importing watchlist: %v\n", err)
+		summary.Failed++
+		return
+	}
+	if stored.Total > 0 {
+		log.Printf("Keeping the %d stored watchlist films\n", stored.Total)
+		summary.Skipped += len(records)
+		return
+	}
+
+	var entries []database.WatchlistEntry
+	for _, r := range records {
+		uri := r.get("Letterboxd URI")
+		if uri == "" || r.get("Name") == "" {
+			summary.Skipped++
+			continue
+		}
+
+		// Rewatch candidates already in the collection keep their film slug
+		id, found, err := i.db.ResolveMovieID(uri, r.get("Name"), r.int("Year"))
+		if err != nil {
+			log.Printf("Error importing watchlist film %s: %v\n", r.get("Name"), err)
+			summary.Failed++
+			continue
+		}
+		if !found {
+			id = importedID(uri)
+		}
+
+		movie := database.Movie{
+			LetterboxdID:  id,
+			Title:         r.get("Name"),
+			Year:          r.int("Year"),
+			LetterboxdURL: uri,
+			LetterboxdURI: uri,
+		}
+		if added, ok := r.date("Date"); ok {
+			movie.DateAdded = added
+		}
+		entries = append(entries, database.WatchlistEntry{Movie: movie, Position: len(entries) + 1})
+	}
+
+	if err := i.db.SaveWatchlist(entries); err != nil {
+		log.Printf("Error importing watchlist: %v\n", err)
+		summary.Failed++
+		return
+	}
+	summary.Watchlist += len(entries)
+}
+
+// importList stores one lists/*.csv file. The file holds a block with the
+// list details followed by a block of its films, each with its own header.
+// Lists that were already scraped are kept, since they are more recent
+func (i *Importer) importList(f *zip.File, summary *Summary) {
+	// The export names each file after the list slug
+	slug := strings.TrimSuffix(strings.TrimPrefix(f.Name, "lists/"), ".csv")
+
+	if _, found, err := i.db.GetList(slug); err != nil || found {
+		if err != nil {
+			log.Printf("Error importing list %s: %v\n", slug, err)
+			summary.Failed++
+		} else {
+			summary.Skipped++
+		}
+		return
+	}
+
+	details, films, err := readListCSV(f)
+	if err != nil {
+		log.Printf("Error reading list %s: %v\n", f.Name, err)
+		summary.Failed++
+		return
+	}
+	if details == nil || details.get("Name") == "" {
+		log.Printf("List %s has no details, skipping\n", f.Name)
+		summary.Skipped++
+		return
+	}
+
+	list := database.List{
+		Slug:        slug,
+		Name:        details.get("Name"),
+		Description: details.get("Description"),
+		URL:         details.get("URL"),
+		FilmCount:   len(films),
+	}
+
+	entries := []database.ListEntry{}
+	for _, r := range films {
+		uri := r.get("URL")
+		id, found, err := i.db.ResolveMovieID(uri, r.get("Name"), r.int("Year"))
+		if err != nil {
+			log.Printf("Error importing %s on list %s: %v\n", r.get("Name"), slug, err)
+			summary.Failed++
+			continue
+		}
+		if !found {
+			// Lists can hold films that were never watched
+			id = importedID(uri)
+		}
+		entries = append(entries, database.ListEntry{
+			Position:      len(entries) + 1,
+			LetterboxdID:  id,
+			Title:         r.get("Name"),
+			LetterboxdURL: uri,
+		})
+	}
+
+	if err := i.db.SaveList(list, entries); err != nil {
+		log.Printf("Error importing list %s: %v\n", slug, err)
+		summary.Failed++
+		return
+	}
+	summary.Lists++
+}
+
+// importViewing upserts a viewing from a diary or review row and returns
+// the letterboxd_id of its film.
+// In these files the Letterboxd URI points at the entry, not the film,
+// so the film is matched by title and year
+func (i *Importer) importViewing(r record, reviewURL string, summary *Summary) (string, error) {
+	watched, ok := r.date("Watched Date")
+	if !ok {
+		return "", fmt.Errorf("missing watched date")
+	}
+
+	film := r.withoutURI()
+	id, err := i.ensureMovie(film, summary)
+	if err != nil {
+		return "", err
+	}
+
+	return id, i.db.AddViewing(database.Viewing{
+		LetterboxdViewingID: database.ViewingKey(id, watched),
+		LetterboxdID:        id,
+		WatchedDate:         watched,
+		Rewatch:             strings.EqualFold(r.get("Rewatch"), "Yes"),
+		Rating:              r.float("Rating"),
+		ReviewURL:           reviewURL,
+		Tags:                r.tags("Tags"),
+	})
+}
+
+// ensureMovie returns the letterboxd_id for the film in the row,
+// creating a minimal movie record when it is not in the database yet.
+// Films created here are keyed by their boxd.it link until a scrape
+// scrapes their film page and moves them to the film slug
+func (i *Importer) ensureMovie(r record, summary *Summary) (string, error) {
+	uri := r.get("Letterboxd URI")
+	title := r.get("Name")
+	year := r.int("Year")
+
+	if title == "" {
+		return "", fmt.Errorf("row has no film name")
+	}
+
+	id, found, err := i.db.ResolveMovieID(uri, title, year)
+	if err != nil {
+		return "", err
+	}
+	if found {
+		if uri != "" {
+			if err := i.db.SetLetterboxdURI(id, uri); err != nil {
+				return "", err
+			}
+		}
+		return id, nil
+	}
+
+	if uri == "" {
+		return "", fmt.Errorf("film %q (%d) is not in the database", title, year)
+	}
+
+	movie := database.Movie{
+		LetterboxdID:  importedID(uri),
+		Title:         title,
+		Year:          year,
+		LetterboxdURL: uri,
+		LetterboxdURI: uri,
+	}
+	if added, ok := r.date("Date"); ok {
+		movie.DateAdded = added
+	}
+
+	if err := i.db.AddMovie(movie); err != nil {
+		return "", err
+	}
+	summary.Films++
+
+	return movie.LetterboxdID, nil
+}
+
+// importedID derives a letterboxd_id from a film's short link
+// From: https://boxd.it/29qU
+// To: boxd.it/29qU
+func importedID(uri string) string {
+	uri = strings.TrimPrefix(uri, "https://")
+	uri = strings.TrimPrefix(uri, "http://")
+	return strings.TrimSuffix(uri, "/")
+}
+
+// record is one CSV row keyed by column header
+type record map[string]string
+
+// get returns a trimmed column value
+func (r record) get(column string) string {
+	return strings.TrimSpace(r[column])
+}
+
+// int safely parses a column to int
+func (r record) int(column string) int {
+	v, _ := strconv.Atoi(r.get(column))
+	return v
+}
+
+// float safely parses a column to float64
+func (r record) float(column string) float64 {
+	v, _ := strconv.ParseFloat(r.get(column), 64)
+	return v
+}
+
+// date parses a YYYY-MM-DD column
+func (r record) date(column string) (time.Time, bool) {
+	parsed, err := time.Parse(exportDateLayout, r.get(column))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return parsed, true
+}
+
+// tags splits a comma-separated tag column into tags, nil when empty
+// From: "theatre, criterion channel" -> theatre, criterion-channel
+func (r record) tags(column string) []database.Term {
+	var tags []database.Term
+	for _, name := range strings.Split(r.get(column), ",") {
+		name = strings.TrimSpace(name)
+		if slug := database.TagSlug(name); slug != "" {
+			tags = append(tags, database.Term{Slug: slug, Name: name})
+		}
+	}
+	return tags
+}
+
+// withoutURI returns a copy of the row with the Letterboxd URI removed
+func (r record) withoutURI() record {
+	film := make(record, len(r))
+	for k, v := range r {
+		film[k] = v
+	}
+	delete(film, "Letterboxd URI")
+	return film
+}
+
+// readListCSV reads a lists/*.csv file: the row under the "Date" header
+// holds the list details, the rows under the "Position" header its films
+func readListCSV(f *zip.File) (details record, films []record, err error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rc.Close()
+
+	reader := csv.NewReader(rc)
+	reader.FieldsPerRecord = -1
+
+	var header []string
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch first := strings.TrimPrefix(row[0], "\ufeff"); {
+		case first == "Date" || first == "Position":
+			header = row
+			continue
+		case header == nil:
+			// e.g. "Letterboxd list export v7"
+			continue
+		}
+
+		r := make(record, len(header))
+		for idx, column := range header {
+			if idx < len(row) {
+				r[column] = row[idx]
+			}
+		}
+		if header[0] == "Position" {
+			films = append(films, r)
+		} else if details == nil {
+			details = r
+		}
+	}
+
+	return details, films, nil
+}
+
+// readCSV reads a CSV file from the archive into header-keyed records
+func readCSV(f *zip.File) ([]record, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	reader := csv.NewReader(rc)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Strip a UTF-8 byte order mark from the first column name
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	var records []record
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		r := make(record, len(header))
+		for idx, column := range header {
+			if idx < len(row) {
+				r[column] = row[idx]
+			}
+		}
+		records = append(records, r)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,296 @@
+package importer
+
+import (
+	"archive/zip"
+	"letterboxd-tracker/database"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// exportFiles is a small Letterboxd export: two watched films, a liked
+// film that was never watched, and one of each other file
+var exportFiles = map[string]string{
+	"watched.csv": `Date,Name,Year,Letterboxd URI
+2024-01-01,Paterson,2016,https://boxd.it/aaa
+2024-01-02,Amélie,2001,https://boxd.it/bbb
+`,
+	"ratings.csv": `Date,Name,Year,Letterboxd URI,Rating
+2024-01-01,Paterson,2016,https://boxd.it/aaa,4.5
+`,
+	"diary.csv": `Date,Name,Year,Letterboxd URI,Rating,Rewatch,Tags,Watched Date
+2024-03-15,Paterson,2016,https://boxd.it/d1,4,,"theatre, with family",2024-03-15
+`,
+	"reviews.csv": `Date,Name,Year,Letterboxd URI,Rating,Rewatch,Review,Tags,Watched Date
+2024-03-15,Paterson,2016,https://boxd.it/r1,4,,A poem of a film.,,2024-03-15
+2024-04-01,Amélie,2001,https://boxd.it/r2,,,Undated thoughts.,,
+`,
+	"likes/films.csv": `Date,Name,Year,Letterboxd URI
+2024-01-05,Paterson,2016,https://boxd.it/aaa
+2024-01-05,Ghost,1990,https://boxd.it/ccc
+`,
+	"watchlist.csv": `Date,Name,Year,Letterboxd URI
+2024-02-01,Ghost,1990,https://boxd.it/ccc
+2024-02-02,Amélie,2001,https://boxd.it/bbb
+`,
+	"lists/best-of-2024.csv": `Letterboxd list export v7
+Date,Name,Tags,URL,Description
+2024-12-30,Best of 2024,,https://letterboxd.com/bob/list/best-of-2024/,My favourites.
+
+Position,Name,Year,URL,Description
+1,Paterson,2016,https://boxd.it/aaa,
+2,Ghost,1990,https://boxd.it/ccc,
+`,
+}
+
+// writeExport writes exportFiles to a ZIP in a temporary directory
+func writeExport(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "export.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create zip: %v", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, body := range exportFiles {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("add %s: %v", name, err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+
+	return path
+}
+
+// newTestDB opens a fully migrated database in a temporary directory
+func newTestDB(t *testing.T) *database.MovieDB {
+	t.Helper()
+	db, err := database.NewMovieDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewMovieDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// countRows counts the rows of each table
+func countRows(t *testing.T, db *database.MovieDB, tables ...string) map[string]int {
+	t.Helper()
+	counts := make(map[string]int)
+	for _, table := range tables {
+		var n int
+		if err := db.GetDB().QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+			t.Fatalf("count %s: %v", table, err)
+		}
+		counts[table] = n
+	}
+	return counts
+}
+
+func TestImportZip(t *testing.T) {
+	db := newTestDB(t)
+	path := writeExport(t)
+
+	summary, err := NewImporter(db).ImportZip(path)
+	if err != nil {
+		t.Fatalf("ImportZip: %v", err)
+	}
+	want := Summary{Films: 2, Ratings: 1, Viewings: 1, Reviews: 2, Liked: 1, Watchlist: 2, Lists: 1, Skipped: 1}
+	if summary != want {
+		t.Errorf("summary = %+v, want %+v", summary, want)
+	}
+
+	// Watched and ratings
+	paterson, found, err := db.GetMovie("boxd.it/aaa")
+	if err != nil || !found {
+		t.Fatalf("GetMovie = found %v, err %v", found, err)
+	}
+	if paterson.Title != "Paterson" || paterson.Year != 2016 || paterson.Rating != 4.5 || !paterson.Liked {
+		t.Errorf("paterson = %+v", paterson)
+	}
+	if amelie, _, _ := db.GetMovie("boxd.it/bbb"); amelie.Liked {
+		t.Error("amelie is liked")
+	}
+
+	// Diary with tags; the review row updates the same viewing
+	viewings, err := db.GetViewings("boxd.it/aaa")
+	if err != nil || len(viewings) != 1 {
+		t.Fatalf("GetViewings = %d viewings, err %v", len(viewings), err)
+	}
+	if viewings[0].LetterboxdViewingID != "boxd.it/aaa:2024-03-15" || viewings[0].ReviewURL != "https://boxd.it/r1" {
+		t.Errorf("viewing = %+v", viewings[0])
+	}
+	wantTags := []database.Term{{Slug: "theatre", Name: "theatre"}, {Slug: "with-family", Name: "with family"}}
+	if !reflect.DeepEqual(viewings[0].Tags, wantTags) {
+		t.Errorf("tags = %v, want %v", viewings[0].Tags, wantTags)
+	}
+
+	// Reviews, dated and undated
+	reviews, err := db.GetReviews("boxd.it/bbb")
+	if err != nil || len(reviews) != 1 {
+		t.Fatalf("GetReviews = %d reviews, err %v", len(reviews), err)
+	}
+	if reviews[0].Key != "boxd.it/bbb:undated" || reviews[0].Text != "Undated thoughts." {
+		t.Errorf("undated review = %q %q", reviews[0].Key, reviews[0].Text)
+	}
+	if reviews, _ := db.GetReviews("boxd.it/aaa"); len(reviews) != 1 || reviews[0].Key != "boxd.it/aaa:2024-03-15" {
+		t.Errorf("dated reviews = %+v", reviews)
+	}
+
+	// Watchlist in file order; Amélie keeps the id of the watched film
+	watchlist, err := db.QueryWatchlist(database.WatchlistQuery{})
+	if err != nil {
+		t.Fatalf("QueryWatchlist: %v", err)
+	}
+	var onList []string
+	for _, entry := range watchlist.Entries {
+		onList = append(onList, entry.Movie.LetterboxdID)
+	}
+	if want := []string{"boxd.it/ccc", "boxd.it/bbb"}; !reflect.DeepEqual(onList, want) {
+		t.Errorf("watchlist = %v, want %v", onList, want)
+	}
+
+	// Lists, including films never watched
+	list, found, err := db.GetList("best-of-2024")
+	if err != nil || !found {
+		t.Fatalf("GetList = found %v, err %v", found, err)
+	}
+	if list.Name != "Best of 2024" || list.Description != "My favourites." || list.FilmCount != 2 {
+		t.Errorf("list = %+v", list)
+	}
+	entries, err := db.GetListEntries("best-of-2024")
+	if err != nil {
+		t.Fatalf("GetListEntries: %v", err)
+	}
+	var listed []string
+	for _, entry := range entries {
+		listed = append(listed, entry.LetterboxdID)
+	}
+	if want := []string{"boxd.it/aaa", "boxd.it/ccc"}; !reflect.DeepEqual(listed, want) {
+		t.Errorf("list entries = %v, want %v", listed, want)
+	}
+}
+
+func TestImportZipTwice(t *testing.T) {
+	db := newTestDB(t)
+	path := writeExport(t)
+	tables := []string{"movies", "viewings", "viewing_tags", "reviews", "watchlist", "lists", "list_entries", "rating_history"}
+
+	if _, err := NewImporter(db).ImportZip(path); err != nil {
+		t.Fatalf("first ImportZip: %v", err)
+	}
+	before := countRows(t, db, tables...)
+
+	summary, err := NewImporter(db).ImportZip(path)
+	if err != nil {
+		t.Fatalf("second ImportZip: %v", err)
+	}
+	if summary.Films != 0 || summary.Failed != 0 {
+		t.Errorf("second import created %d films, failed %d", summary.Films, summary.Failed)
+	}
+	if after := countRows(t, db, tables...); !reflect.DeepEqual(after, before) {
+		t.Errorf("rows after re-import = %v, want %v", after, before)
+	}
+}
+
+func TestImportedFilmsRekeyedToSlug(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := NewImporter(db).ImportZip(writeExport(t)); err != nil {
+		t.Fatalf("ImportZip: %v", err)
+	}
+
+	// A scrape finds the imported films by their boxd.it links
+	for _, film := range []struct{ uri, title, slug string }{
+		{"https://boxd.it/aaa", "Paterson", "/film/paterson"},
+		{"https://boxd.it/bbb", "Amélie", "/film/amelie"},
+	} {
+		oldID, found, err := db.FindImportedMovie(film.uri, film.title, 0)
+		if err != nil || !found {
+			t.Fatalf("FindImportedMovie(%s) = found %v, err %v", film.uri, found, err)
+		}
+		if err := db.RekeyMovie(oldID, film.slug); err != nil {
+			t.Fatalf("RekeyMovie(%s): %v", oldID, err)
+		}
+	}
+
+	if _, found, _ := db.GetMovie("boxd.it/aaa"); found {
+		t.Error("old id still stored")
+	}
+	paterson, found, err := db.GetMovie("/film/paterson")
+	if err != nil || !found {
+		t.Fatalf("GetMovie = found %v, err %v", found, err)
+	}
+	if !paterson.Liked || paterson.Rating != 4.5 {
+		t.Errorf("like or rating lost: %+v", paterson)
+	}
+
+	viewings, err := db.GetViewings("/film/paterson")
+	if err != nil || len(viewings) != 1 {
+		t.Fatalf("GetViewings = %d viewings, err %v", len(viewings), err)
+	}
+	if viewings[0].LetterboxdViewingID != "/film/paterson:2024-03-15" || len(viewings[0].Tags) != 2 {
+		t.Errorf("viewing = %q with %d tags", viewings[0].LetterboxdViewingID, len(viewings[0].Tags))
+	}
+
+	reviews, err := db.GetReviews("/film/paterson")
+	if err != nil || len(reviews) != 1 || reviews[0].Key != "/film/paterson:2024-03-15" {
+		t.Errorf("dated reviews = %+v, err %v", reviews, err)
+	}
+
+	// The scraped copy of the undated review replaces the imported one
+	err = db.SaveReview(database.Review{
+		Key:          database.ReviewKey("/film/amelie", time.Time{}),
+		LetterboxdID: "/film/amelie",
+		URL:          "https://letterboxd.com/bob/film/amelie/",
+		Text:         "Undated thoughts.",
+		LikeCount:    2,
+	})
+	if err != nil {
+		t.Fatalf("SaveReview: %v", err)
+	}
+	if reviews, _ := db.GetReviews("/film/amelie"); len(reviews) != 1 || reviews[0].LikeCount != 2 {
+		t.Errorf("undated reviews = %+v, want the scraped copy only", reviews)
+	}
+
+	entries, _ := db.GetListEntries("best-of-2024")
+	if len(entries) != 2 || entries[0].LetterboxdID != "/film/paterson" {
+		t.Errorf("list entries = %+v", entries)
+	}
+
+	// A watched film's watchlist entry moves with it; an unwatched one is
+	// claimed by the first sync that adds its slug
+	if _, onList, _ := db.GetWatchlistEntry("/film/amelie"); !onList {
+		t.Error("watchlist entry of amelie not rekeyed")
+	}
+	err = db.AddToWatchlist([]database.WatchlistEntry{{
+		Movie:    database.Movie{LetterboxdID: "/film/ghost", Title: "Ghost", Year: 1990, LetterboxdURI: "https://boxd.it/ccc"},
+		Position: 1,
+	}})
+	if err != nil {
+		t.Fatalf("AddToWatchlist: %v", err)
+	}
+	watchlist, err := db.QueryWatchlist(database.WatchlistQuery{})
+	if err != nil {
+		t.Fatalf("QueryWatchlist: %v", err)
+	}
+	var onList []string
+	for _, entry := range watchlist.Entries {
+		onList = append(onList, entry.Movie.LetterboxdID)
+	}
+	if want := []string{"/film/ghost", "/film/amelie"}; !reflect.DeepEqual(onList, want) {
+		t.Errorf("watchlist = %v, want %v", onList, want)
+	}
+	if added := watchlist.Entries[0].Movie.DateAdded; !added.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("date added = %v, want the imported 2024-02-01", added)
+	}
+}
@@ -0,0 +1,81 @@
+// Package report renders collection summaries as self-contained HTML files
+package report
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"letterboxd-tracker/database"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"stars":     stars,
+	"signed":    signed,
+	"monthName": monthName,
+	"percent":   percent,
+	"maxMonth":  maxMonth,
+}).ParseFS(templateFS, "templates/*.html"))
+
+// WriteYearInReview renders a year-in-review as a standalone HTML page.
+// Styles are inlined and no external resources are referenced,
+// so the file can be opened or shared on its own
+func WriteYearInReview(w io.Writer, review database.YearInReview) error {
+	data := struct {
+		database.YearInReview
+		GeneratedAt time.Time
+	}{review, time.Now()}
+
+	if err := templates.ExecuteTemplate(w, "year_in_review.html", data); err != nil {
+		return fmt.Errorf("failed to render year in review: %w", err)
+	}
+	return nil
+}
+
+// stars formats a rating the way Letterboxd shows it, e.g. 3.5 -> "★★★½"
+func stars(rating float64) string {
+	if rating <= 0 {
+		return ""
+	}
+	full := int(rating)
+	out := strings.Repeat("★", full)
+	if rating-float64(full) >= 0.5 {
+		out += "½"
+	}
+	return out
+}
+
+// signed formats a rating difference with an explicit sign, e.g. "+1.25"
+func signed(diff float64) string {
+	return fmt.Sprintf("%+.2f", diff)
+}
+
+// monthName returns the short English name of a month number (1-12)
+func monthName(month int) string {
+	return time.Month(month).String()[:3]
+}
+
+// percent returns part as a percentage of whole, 0 when whole is 0
+func percent(part, whole int) int {
+	if whole <= 0 {
+		return 0
+	}
+	return part * 100 / whole
+}
+
+// maxMonth returns the largest monthly count, for scaling the chart
+func maxMonth(months []database.MonthCount) int {
+	max := 0
+	for _, month := range months {
+		if month.Count > max {
+			max = month.Count
+		}
+	}
+	return max
+}
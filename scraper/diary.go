@@ -0,0 +1,134 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"letterboxd-tracker/database"
+	"log"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// scrapeDiary scrapes every page of the user's diary to collect dated viewings
+// Follows pagination using the .next selector. The diary is newest first,
+// so in incremental mode paging stops after a run of known viewings
+func (s *Scraper) scrapeDiary(ctx context.Context, username string, opts SyncOptions) ([]database.Viewing, error) {
+	var allViewings []database.Viewing
+	pageNum := 1
+	knownStreak := 0
+
+	for {
+		url := fmt.Sprintf("https://letterboxd.com/%s/films/diary/page/%d/", username, pageNum)
+		log.Printf("Scraping diary page %d: %s\n", pageNum, url)
+
+		pageViewings, hasNext, err := s.scrapeDiaryPage(ctx, url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scrape diary page %d: %w", pageNum, err)
+		}
+
+		reachedKnown := false
+		if opts.Incremental {
+			pageViewings, reachedKnown, err = takeUntilKnown(pageViewings, &knownStreak, opts.KnownStreak,
+				func(viewing database.Viewing) (bool, error) { return s.db.ViewingExists(viewing.LetterboxdViewingID) })
+			if err != nil {
+				return nil, err
+			}
+		}
+		allViewings = append(allViewings, pageViewings...)
+		s.report(Progress{Phase: PhaseDiary, Page: pageNum, Total: len(allViewings)})
+
+		if reachedKnown || !hasNext {
+			break
+		}
+
+		pageNum++
+	}
+
+	return allViewings, nil
+}
+
+// scrapeDiaryPage scrapes a single diary page
+func (s *Scraper) scrapeDiaryPage(ctx context.Context, url string) ([]database.Viewing, bool, error) {
+	var viewings []database.Viewing
+	hasNext := false
+
+	c := s.newCollector(ctx)
+
+	// Handle errors
+	c.OnError(func(_ *colly.Response, err error) {
+		log.Printf("Error scraping diary: %v\n", err)
+	})
+
+	// Extract one viewing per diary row
+	c.OnHTML("tr.diary-entry-row", func(e *colly.HTMLElement) {
+		// The day link carries the full watched date
+		// e.g. /username/films/diary/for/2024/03/15/
+		watched, ok := parseDiaryDate(e.ChildAttr("td.td-day a", "href"))
+		if !ok {
+			return
+		}
+
+		filmLink := e.ChildAttr("td.td-film-details div.react-component[data-item-link]", "data-item-link")
+		if filmLink == "" {
+			filmLink = e.ChildAttr("td.td-film-details div[data-film-link]", "data-film-link")
+		}
+		letterboxdID := extractLetterboxdID(filmLink)
+		if letterboxdID == "" {
+			return
+		}
+
+		// Extract rating at this viewing
+		var rating float64
+		if ratingText := strings.TrimSpace(e.ChildText("td.td-rating span.rating")); ratingText != "" {
+			if r, err := symbolToRating(ratingText); err == nil {
+				rating = r
+			}
+		}
+
+		// Rewatches are marked by the rewatch icon being switched on
+		rewatch := e.DOM.Find("td.td-rewatch").Length() > 0 &&
+			!e.DOM.Find("td.td-rewatch").HasClass("icon-status-off")
+
+		reviewURL := e.ChildAttr("td.td-review a", "href")
+		if reviewURL != "" && !strings.HasPrefix(reviewURL, "http") {
+			reviewURL = "https://letterboxd.com" + reviewURL
+		}
+
+		// Tags link to the user's tag pages, e.g. /username/tag/theatre/diary/
+		var tags []database.Term
+		e.ForEach("a[href*='/tag/']", func(_ int, a *colly.HTMLElement) {
+			slug := extractTagSlug(a.Attr("href"))
+			if slug == "" {
+				return
+			}
+			name := strings.TrimSpace(a.Text)
+			if name == "" {
+				name = slug
+			}
+			tags = append(tags, database.Term{Slug: slug, Name: name})
+		})
+
+		viewings = append(viewings, database.Viewing{
+			LetterboxdViewingID: database.ViewingKey(letterboxdID, watched),
+			LetterboxdID:        letterboxdID,
+			WatchedDate:         watched,
+			Rewatch:             rewatch,
+			Rating:              rating,
+			ReviewURL:           reviewURL,
+			Tags:                tags,
+		})
+	})
+
+	// Check for next page
+	c.OnHTML("a[class=next]", func(e *colly.HTMLElement) {
+		hasNext = true
+	})
+
+	err := c.Visit(url)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to visit page: %w", err)
+	}
+
+	return viewings, hasNext, nil
+}
@@ -0,0 +1,51 @@
+package scraper
+
+import (
+	"context"
+	"letterboxd-tracker/database"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScrapeDiaryPage(t *testing.T) {
+	s := newFixtureScraper(t)
+
+	viewings, hasNext, err := s.scrapeDiaryPage(context.Background(), "https://letterboxd.com/bob/films/diary/page/1/")
+	if err != nil {
+		t.Fatalf("scrapeDiaryPage: %v", err)
+	}
+	if !hasNext {
+		t.Error("hasNext = false, want true")
+	}
+	// The row without a dated day link is skipped
+	if len(viewings) != 2 {
+		t.Fatalf("got %d viewings, want 2", len(viewings))
+	}
+
+	first := viewings[0]
+	if first.LetterboxdViewingID != "/film/paterson:2024-03-15" || first.LetterboxdID != "/film/paterson" {
+		t.Errorf("first viewing = %q of %q", first.LetterboxdViewingID, first.LetterboxdID)
+	}
+	if !first.WatchedDate.Equal(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("watched = %v, want 2024-03-15", first.WatchedDate)
+	}
+	if first.Rating != 4 || first.Rewatch || first.ReviewURL != "" {
+		t.Errorf("rating %v, rewatch %v, review %q, want 4, false, none", first.Rating, first.Rewatch, first.ReviewURL)
+	}
+	wantTags := []database.Term{{Slug: "theatre", Name: "theatre"}, {Slug: "with-family", Name: "with family"}}
+	if !reflect.DeepEqual(first.Tags, wantTags) {
+		t.Errorf("tags = %v, want %v", first.Tags, wantTags)
+	}
+
+	second := viewings[1]
+	if second.LetterboxdViewingID != "/film/amelie:2024-03-01" {
+		t.Errorf("second viewing = %q", second.LetterboxdViewingID)
+	}
+	if second.Rating != 0 || !second.Rewatch || second.ReviewURL != "https://letterboxd.com/bob/film/amelie/1/" {
+		t.Errorf("rating %v, rewatch %v, review %q", second.Rating, second.Rewatch, second.ReviewURL)
+	}
+	if second.Tags != nil {
+		t.Errorf("tags = %v, want nil", second.Tags)
+	}
+}
@@ -0,0 +1,101 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Error kinds returned by fetchers and the scraper. Use errors.Is to classify
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrRateLimited = errors.New("rate limited")
+	ErrServer      = errors.New("server error")
+	ErrNetwork     = errors.New("network error")
+	ErrParse       = errors.New("parse error")
+)
+
+// FetchError describes a failed page load
+type FetchError struct {
+	URL        string
+	StatusCode int
+	// RetryAfter is the delay requested by the server, zero if none was sent
+	RetryAfter time.Duration
+	Kind       error
+	Err        error
+}
+
+// Error implements the error interface
+func (e *FetchError) Error() string {
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("%v fetching %s: %v", e.Kind, e.URL, e.Err)
+	case e.StatusCode != 0:
+		return fmt.Sprintf("%v fetching %s: HTTP %d", e.Kind, e.URL, e.StatusCode)
+	default:
+		return fmt.Sprintf("%v fetching %s", e.Kind, e.URL)
+	}
+}
+
+// Unwrap exposes the error kind to errors.Is
+func (e *FetchError) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+// statusError classifies a non-200 HTTP response
+func statusError(url string, resp *http.Response) *FetchError {
+	fe := &FetchError{URL: url, StatusCode: resp.StatusCode}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		fe.Kind = ErrNotFound
+	case resp.StatusCode == http.StatusTooManyRequests:
+		fe.Kind = ErrRateLimited
+		fe.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	case resp.StatusCode >= 500:
+		fe.Kind = ErrServer
+		fe.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	default:
+		fe.Kind = fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return fe
+}
+
+// parseRetryAfter reads a Retry-After header, given either as
+// delay seconds ("120") or an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+
+	return 0
+}
+
+// isRetryable reports whether a failed request is worth trying again
+func isRetryable(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrServer) || errors.Is(err, ErrNetwork)
+}
+
+// errorKind names the class of an error for logs
+func errorKind(err error) string {
+	for _, kind := range []error{ErrNotFound, ErrRateLimited, ErrServer, ErrNetwork, ErrParse} {
+		if errors.Is(err, kind) {
+			return kind.Error()
+		}
+	}
+	return "error"
+}
@@ -0,0 +1,65 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"letterboxd-tracker/database"
+	"log"
+)
+
+// syncLikes scrapes the pages of the user's liked films and marks those
+// films as liked. The films grid already shows the heart, but only the
+// likes list reaches films skipped by an incremental sync.
+// The list is most recently liked first, so in incremental mode paging
+// stops after a run of films already marked as liked and new likes are
+// only added; otherwise the whole list is read and exactly those films
+// stay liked. Returns how many liked films were found; nothing is saved
+// unless the pages were read without error
+func (s *Scraper) syncLikes(ctx context.Context, username string, opts SyncOptions) (int, error) {
+	var likedIDs []string
+	pageNum := 1
+	knownStreak := 0
+
+	for {
+		url := fmt.Sprintf("https://letterboxd.com/%s/likes/films/page/%d/", username, pageNum)
+		log.Printf("Scraping likes page %d: %s\n", pageNum, url)
+
+		pageFilms, hasNext, err := s.scrapeFilmsPage(ctx, url)
+		if err != nil {
+			return 0, fmt.Errorf("failed to scrape likes page %d: %w", pageNum, err)
+		}
+
+		reachedKnown := false
+		if opts.Incremental {
+			pageFilms, reachedKnown, err = takeUntilKnown(pageFilms, &knownStreak, opts.KnownStreak,
+				func(film database.Movie) (bool, error) { return s.db.IsLiked(film.LetterboxdID) })
+			if err != nil {
+				return 0, err
+			}
+		}
+		for _, film := range pageFilms {
+			likedIDs = append(likedIDs, film.LetterboxdID)
+		}
+		s.report(Progress{Phase: PhaseLikes, Page: pageNum, Total: len(likedIDs)})
+
+		if reachedKnown || !hasNext {
+			break
+		}
+
+		pageNum++
+	}
+
+	var changed int
+	var err error
+	if opts.Incremental {
+		changed, err = s.db.MarkLiked(likedIDs)
+	} else {
+		changed, err = s.db.SetLikedFilms(likedIDs)
+	}
+	if err != nil {
+		return 0, err
+	}
+	log.Printf("Updated the like of %d films\n", changed)
+
+	return len(likedIDs), nil
+}
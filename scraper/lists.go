@@ -0,0 +1,229 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"letterboxd-tracker/database"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// syncLists scrapes the user's lists and the films of each list. A list
+// whose Letterboxd update time is unchanged keeps its stored entries unless
+// refreshing, so its diff still shows the last real change. Lists deleted on
+// Letterboxd are removed once the whole index was read.
+// Returns how many lists were synced and how many failed
+func (s *Scraper) syncLists(ctx context.Context, username string, opts SyncOptions) (synced, failed int, err error) {
+	var lists []database.List
+	pageNum := 1
+
+	for {
+		url := fmt.Sprintf("https://letterboxd.com/%s/lists/page/%d/", username, pageNum)
+		log.Printf("Scraping lists page %d: %s\n", pageNum, url)
+
+		pageLists, hasNext, err := s.scrapeListsPage(ctx, url)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to scrape lists page %d: %w", pageNum, err)
+		}
+		lists = append(lists, pageLists...)
+		s.report(Progress{Phase: PhaseLists, Page: pageNum, Total: len(lists)})
+
+		if !hasNext {
+			break
+		}
+
+		pageNum++
+	}
+
+	slugs := make([]string, 0, len(lists))
+	for i, list := range lists {
+		if err := ctx.Err(); err != nil {
+			return synced, failed, err
+		}
+		slugs = append(slugs, list.Slug)
+
+		if err := s.syncList(ctx, list, opts); err != nil {
+			if ctx.Err() != nil {
+				return synced, failed, ctx.Err()
+			}
+			log.Printf("Error scraping list %s (%s): %v\n", list.Name, errorKind(err), err)
+			failed++
+		} else {
+			synced++
+		}
+
+		s.report(Progress{Phase: PhaseLists, Page: pageNum, Current: i + 1, Total: len(lists), Title: list.Name})
+	}
+
+	if err := s.db.DeleteListsExcept(slugs); err != nil {
+		return synced, failed, err
+	}
+
+	return synced, failed, nil
+}
+
+// syncList reads the first page of a list for its ranking and update time,
+// then every page of its films if the list changed since the last sync
+func (s *Scraper) syncList(ctx context.Context, list database.List, opts SyncOptions) error {
+	var entries []database.ListEntry
+	pageNum := 1
+
+	for {
+		path := strings.Trim(strings.TrimPrefix(list.URL, "https://letterboxd.com"), "/")
+		url := fmt.Sprintf("https://letterboxd.com/%s/page/%d/", path, pageNum)
+		page, err := s.scrapeListPage(ctx, url)
+		if err != nil {
+			return fmt.Errorf("failed to scrape page %d: %w", pageNum, err)
+		}
+
+		if pageNum == 1 {
+			list.Ranked = page.ranked
+			list.UpdatedAt = page.updatedAt
+
+			stored, found, err := s.db.GetList(list.Slug)
+			if err != nil {
+				return err
+			}
+			if found && !opts.Refresh && !list.UpdatedAt.IsZero() &&
+				stored.UpdatedAt.Unix() == list.UpdatedAt.Unix() {
+				log.Printf("List unchanged since last sync: %s\n", list.Name)
+				return s.db.SaveList(list, nil)
+			}
+		}
+
+		for _, entry := range page.entries {
+			entry.Position = len(entries) + 1
+			entries = append(entries, entry)
+		}
+
+		if !page.hasNext {
+			break
+		}
+
+		pageNum++
+	}
+
+	log.Printf("Scraped %d films from list: %s\n", len(entries), list.Name)
+	if entries == nil {
+		entries = []database.ListEntry{}
+	}
+	return s.db.SaveList(list, entries)
+}
+
+// scrapeListsPage scrapes a single page of the user's lists index
+func (s *Scraper) scrapeListsPage(ctx context.Context, url string) ([]database.List, bool, error) {
+	var lists []database.List
+	hasNext := false
+
+	c := s.newCollector(ctx)
+
+	// Handle errors
+	c.OnError(func(_ *colly.Response, err error) {
+		log.Printf("Error scraping lists: %v\n", err)
+	})
+
+	// One summary block per list
+	c.OnHTML("section.list, article.list-summary", func(e *colly.HTMLElement) {
+		href := e.ChildAttr("h2 a", "href")
+		slug := extractListSlug(href)
+		name := strings.TrimSpace(e.ChildText("h2 a"))
+		if slug == "" || name == "" {
+			return
+		}
+
+		// e.g. "12 films" or "1,204 films"
+		count := 0
+		if fields := strings.Fields(e.ChildText(".value")); len(fields) > 0 {
+			count = parseInt(strings.ReplaceAll(fields[0], ",", ""))
+		}
+
+		lists = append(lists, database.List{
+			Slug:        slug,
+			Name:        name,
+			Description: strings.TrimSpace(e.ChildText(".body-text, .notes")),
+			URL:         href,
+			FilmCount:   count,
+		})
+	})
+
+	// Check for next page
+	c.OnHTML("a[class=next]", func(e *colly.HTMLElement) {
+		hasNext = true
+	})
+
+	err := c.Visit(url)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to visit page: %w", err)
+	}
+
+	return lists, hasNext, nil
+}
+
+// listPage is what one page of a list yields. ranked and updatedAt are
+// read from every page but only used from the first
+type listPage struct {
+	entries   []database.ListEntry
+	ranked    bool
+	updatedAt time.Time
+	hasNext   bool
+}
+
+// scrapeListPage scrapes a single page of a list's films
+func (s *Scraper) scrapeListPage(ctx context.Context, url string) (listPage, error) {
+	var page listPage
+
+	c := s.newCollector(ctx)
+
+	// Handle errors
+	c.OnError(func(_ *colly.Response, err error) {
+		log.Printf("Error scraping list: %v\n", err)
+	})
+
+	// Ranked lists number their posters
+	c.OnHTML("ol.poster-list, ul.poster-list.-numbered, p.list-number", func(e *colly.HTMLElement) {
+		page.ranked = true
+	})
+
+	// Published and updated times; the latest one is the last update
+	c.OnHTML(".list-date time[datetime], .updated time[datetime], .published time[datetime]", func(e *colly.HTMLElement) {
+		if t, err := time.Parse(time.RFC3339, e.Attr("datetime")); err == nil && t.After(page.updatedAt) {
+			page.updatedAt = t
+		}
+	})
+
+	// One poster per film, in list order
+	c.OnHTML("li.posteritem, li.poster-container, li.griditem", func(e *colly.HTMLElement) {
+		link := e.ChildAttr("[data-item-link]", "data-item-link")
+		title := e.ChildAttr("[data-item-name]", "data-item-name")
+		if link == "" {
+			link = e.ChildAttr("[data-film-link]", "data-film-link")
+			title = e.ChildAttr("img", "alt")
+		}
+
+		letterboxdID := extractLetterboxdID(link)
+		if letterboxdID == "" {
+			return
+		}
+
+		page.entries = append(page.entries, database.ListEntry{
+			LetterboxdID:  letterboxdID,
+			Title:         title,
+			LetterboxdURL: link,
+		})
+	})
+
+	// Check for next page
+	c.OnHTML("a[class=next]", func(e *colly.HTMLElement) {
+		page.hasNext = true
+	})
+
+	err := c.Visit(url)
+	if err != nil {
+		return page, fmt.Errorf("failed to visit page: %w", err)
+	}
+
+	return page, nil
+}
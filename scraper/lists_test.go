@@ -0,0 +1,67 @@
+package scraper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScrapeListsPage(t *testing.T) {
+	s := newFixtureScraper(t)
+
+	lists, hasNext, err := s.scrapeListsPage(context.Background(), "https://letterboxd.com/bob/lists/page/1/")
+	if err != nil {
+		t.Fatalf("scrapeListsPage: %v", err)
+	}
+	if hasNext {
+		t.Error("hasNext = true on the last page")
+	}
+	if len(lists) != 2 {
+		t.Fatalf("got %d lists, want 2", len(lists))
+	}
+
+	tests := []struct {
+		slug        string
+		name        string
+		description string
+		count       int
+	}{
+		{"best-of-2024", "Best of 2024", "My favourites.", 3},
+		{"to-rewatch", "To rewatch", "", 1},
+	}
+	for i, tt := range tests {
+		l := lists[i]
+		if l.Slug != tt.slug || l.Name != tt.name || l.Description != tt.description || l.FilmCount != tt.count {
+			t.Errorf("list %d = %q %q %q %d, want %q %q %q %d",
+				i, l.Slug, l.Name, l.Description, l.FilmCount, tt.slug, tt.name, tt.description, tt.count)
+		}
+	}
+}
+
+func TestScrapeListPage(t *testing.T) {
+	s := newFixtureScraper(t)
+
+	page, err := s.scrapeListPage(context.Background(), "https://letterboxd.com/bob/list/best-of-2024/page/1/")
+	if err != nil {
+		t.Fatalf("scrapeListPage: %v", err)
+	}
+	if !page.ranked {
+		t.Error("ranked = false for a numbered list")
+	}
+	if !page.hasNext {
+		t.Error("hasNext = false, want true")
+	}
+	if want := time.Date(2024, 12, 30, 10, 0, 0, 0, time.UTC); !page.updatedAt.Equal(want) {
+		t.Errorf("updatedAt = %v, want %v", page.updatedAt, want)
+	}
+
+	var got []string
+	for _, entry := range page.entries {
+		got = append(got, entry.LetterboxdID+" "+entry.Title)
+	}
+	want := []string{"/film/paterson Paterson (2016)", "/film/amelie Amélie"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("entries = %q, want %q", got, want)
+	}
+}
@@ -4,21 +4,22 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // symbolToRating converts Letterboxd star symbols to numeric ratings
 func symbolToRating(symbol string) (float64, error) {
 	ratingMap := map[string]float64{
-		"½":       0.5,
-		"★":       1.0,
-		"★½":      1.5,
-		"★★":      2.0,
-		"★★½":     2.5,
-		"★★★":     3.0,
-		"★★★½":    3.5,
-		"★★★★":    4.0,
-		"★★★★½":   4.5,
-		"★★★★★":   5.0,
+		"½":     0.5,
+		"★":     1.0,
+		"★½":    1.5,
+		"★★":    2.0,
+		"★★½":   2.5,
+		"★★★":   3.0,
+		"★★★½":  3.5,
+		"★★★★":  4.0,
+		"★★★★½": 4.5,
+		"★★★★★": 5.0,
 	}
 
 	if rating, exists := ratingMap[symbol]; exists {
@@ -42,6 +43,92 @@ func extractLetterboxdID(url string) string {
 	return url
 }
 
+// parseDiaryDate extracts the watched date from a diary day link
+// From: /username/films/diary/for/2024/03/15/
+// To: 2024-03-15
+func parseDiaryDate(href string) (time.Time, bool) {
+	_, rest, found := strings.Cut(href, "/for/")
+	if !found {
+		return time.Time{}, false
+	}
+
+	parsed, err := time.Parse("2006/01/02", strings.Trim(rest, "/"))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return parsed, true
+}
+
+// extractPersonSlug extracts the person identifier from a Letterboxd person link
+// From: /director/jim-jarmusch/
+// To: jim-jarmusch
+func extractPersonSlug(href string) string {
+	href = strings.TrimPrefix(href, "https://letterboxd.com")
+	href = strings.Trim(href, "/")
+
+	parts := strings.Split(href, "/")
+	if len(parts) < 2 {
+		return ""
+	}
+
+	return parts[len(parts)-1]
+}
+
+// extractTermSlug extracts the slug of a genre, country, language, studio or
+// theme link whose path starts with one of the given prefixes
+// From: "/films/genre/science-fiction/" with prefix "/films/genre/" -> "science-fiction"
+func extractTermSlug(href string, prefixes ...string) string {
+	href = strings.TrimPrefix(href, "https://letterboxd.com")
+
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(href, prefix) {
+			slug := strings.Trim(strings.TrimPrefix(href, prefix), "/")
+			if slug != "" && !strings.Contains(slug, "/") {
+				return slug
+			}
+		}
+	}
+
+	return ""
+}
+
+// extractImdbID extracts the IMDb id from an IMDb link
+// From: "http://www.imdb.com/title/tt0211915/maindetails" -> "tt0211915"
+func extractImdbID(href string) string {
+	for _, part := range strings.Split(href, "/") {
+		if len(part) > 2 && strings.HasPrefix(part, "tt") && parseInt(part[2:]) > 0 {
+			return part
+		}
+	}
+	return ""
+}
+
+// extractTmdbID extracts the TMDB type and id from a TMDB link
+// From: "https://www.themoviedb.org/movie/194/" -> "movie", 194
+func extractTmdbID(href string) (string, int) {
+	parts := strings.Split(strings.Trim(href, "/"), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "movie" || parts[i] == "tv" {
+			if id := parseInt(parts[i+1]); id > 0 {
+				return parts[i], id
+			}
+		}
+	}
+	return "", 0
+}
+
+// extractListSlug extracts the list identifier from a list link
+// From: "/username/list/best-of-2024/" -> "best-of-2024"
+func extractListSlug(href string) string {
+	_, rest, found := strings.Cut(href, "/list/")
+	if !found {
+		return ""
+	}
+	slug, _, _ := strings.Cut(strings.Trim(rest, "/"), "/")
+	return slug
+}
+
 // parseRuntime extracts runtime in minutes from runtime text
 // From: "148 mins More" -> 148
 // From: "2h 28m" -> 148
@@ -107,3 +194,28 @@ func parseRating(text string) float64 {
 	}
 	return 0
 }
+
+// extractReviewFilmLink derives the film link from a review link
+// From: "/username/film/paterson/1/" -> "/film/paterson/"
+func extractReviewFilmLink(href string) string {
+	_, rest, found := strings.Cut(href, "/film/")
+	if !found {
+		return ""
+	}
+	slug, _, _ := strings.Cut(strings.Trim(rest, "/"), "/")
+	if slug == "" {
+		return ""
+	}
+	return "/film/" + slug + "/"
+}
+
+// extractTagSlug extracts the tag identifier from a tag link
+// From: "/username/tag/criterion-channel/diary/" -> "criterion-channel"
+func extractTagSlug(href string) string {
+	_, rest, found := strings.Cut(href, "/tag/")
+	if !found {
+		return ""
+	}
+	slug, _, _ := strings.Cut(strings.Trim(rest, "/"), "/")
+	return slug
+}
@@ -0,0 +1,107 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExtractListSlug(t *testing.T) {
+	tests := []struct {
+		href string
+		want string
+	}{
+		{"/bob/list/best-of-2024/", "best-of-2024"},
+		{"https://letterboxd.com/bob/list/best-of-2024/page/2/", "best-of-2024"},
+		{"/bob/list/to-rewatch", "to-rewatch"},
+		{"/bob/lists/", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := extractListSlug(tt.href); got != tt.want {
+			t.Errorf("extractListSlug(%q) = %q, want %q", tt.href, got, tt.want)
+		}
+	}
+}
+
+func TestExtractReviewFilmLink(t *testing.T) {
+	tests := []struct {
+		href string
+		want string
+	}{
+		{"/bob/film/paterson/", "/film/paterson/"},
+		{"/bob/film/paterson/1/", "/film/paterson/"},
+		{"https://letterboxd.com/bob/film/paterson/2/", "/film/paterson/"},
+		{"/bob/film/", ""},
+		{"/bob/films/reviews/", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := extractReviewFilmLink(tt.href); got != tt.want {
+			t.Errorf("extractReviewFilmLink(%q) = %q, want %q", tt.href, got, tt.want)
+		}
+	}
+}
+
+func TestExtractTagSlug(t *testing.T) {
+	tests := []struct {
+		href string
+		want string
+	}{
+		{"/bob/tag/criterion-channel/diary/", "criterion-channel"},
+		{"/bob/tag/theatre/", "theatre"},
+		{"https://letterboxd.com/bob/tag/with-family/films/", "with-family"},
+		{"/bob/tags/", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := extractTagSlug(tt.href); got != tt.want {
+			t.Errorf("extractTagSlug(%q) = %q, want %q", tt.href, got, tt.want)
+		}
+	}
+}
+
+func TestParseDiaryDate(t *testing.T) {
+	tests := []struct {
+		href   string
+		want   time.Time
+		wantOK bool
+	}{
+		{"/bob/films/diary/for/2024/03/15/", time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), true},
+		{"https://letterboxd.com/bob/films/diary/for/1999/12/31", time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC), true},
+		{"/bob/films/diary/for/2024/03/", time.Time{}, false},
+		{"/bob/films/diary/for/2024/02/30/", time.Time{}, false},
+		{"/bob/films/diary/", time.Time{}, false},
+		{"", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseDiaryDate(tt.href)
+		if ok != tt.wantOK || !got.Equal(tt.want) {
+			t.Errorf("parseDiaryDate(%q) = %v, %v, want %v, %v", tt.href, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestExtractTmdbID(t *testing.T) {
+	tests := []struct {
+		href     string
+		wantType string
+		wantID   int
+	}{
+		{"https://www.themoviedb.org/movie/194/", "movie", 194},
+		{"https://www.themoviedb.org/tv/1396", "tv", 1396},
+		{"https://www.themoviedb.org/movie/", "", 0},
+		{"https://www.themoviedb.org/person/500/", "", 0},
+		{"", "", 0},
+	}
+
+	for _, tt := range tests {
+		gotType, gotID := extractTmdbID(tt.href)
+		if gotType != tt.wantType || gotID != tt.wantID {
+			t.Errorf("extractTmdbID(%q) = %q, %d, want %q, %d", tt.href, gotType, gotID, tt.wantType, tt.wantID)
+		}
+	}
+}
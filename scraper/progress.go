@@ -0,0 +1,61 @@
+package scraper
+
+import (
+	"context"
+	"time"
+)
+
+// Scrape phases reported through Progress
+const (
+	PhaseList      = "list"
+	PhaseDetails   = "details"
+	PhaseDiary     = "diary"
+	PhaseLikes     = "likes"
+	PhaseWatchlist = "watchlist"
+	PhaseLists     = "lists"
+	PhaseReviews   = "reviews"
+	PhaseDone      = "done"
+)
+
+// Progress is a snapshot of a running scrape, sent to the progress handler
+// whenever a page or film is processed
+type Progress struct {
+	Phase     string `json:"phase"`
+	Page      int    `json:"page"`
+	Current   int    `json:"current"`
+	Total     int    `json:"total"`
+	Scraped   int    `json:"scraped"`
+	Refreshed int    `json:"refreshed"`
+	Skipped   int    `json:"skipped"`
+	Failed    int    `json:"failed"`
+	Title     string `json:"title"`
+}
+
+// ProgressFunc receives progress updates during a scrape.
+// It is called synchronously, so it should return quickly
+type ProgressFunc func(Progress)
+
+// SetProgressHandler registers a function that receives progress updates
+func (s *Scraper) SetProgressHandler(fn ProgressFunc) {
+	s.onProgress = fn
+}
+
+// report sends a progress update if a handler is registered
+func (s *Scraper) report(p Progress) {
+	if s.onProgress != nil {
+		s.onProgress(p)
+	}
+}
+
+// sleep pauses for d, returning early with the context error if cancelled
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,113 @@
+package scraper
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimit configures how hard the scraper may hit Letterboxd.
+// Every page load, from any worker, draws from the same token bucket
+type RateLimit struct {
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	Burst             int     `json:"burst"`
+	MaxInFlight       int     `json:"max_in_flight"`
+}
+
+// DefaultRateLimit is a polite default: a sustained 4 requests/sec,
+// short bursts of 4 and at most 4 film pages being scraped at once
+func DefaultRateLimit() RateLimit {
+	return RateLimit{RequestsPerSecond: 4, Burst: 4, MaxInFlight: 4}
+}
+
+// SetRateLimit replaces the scraper's rate limit.
+// Zero or negative fields fall back to the defaults
+func (s *Scraper) SetRateLimit(limit RateLimit) {
+	defaults := DefaultRateLimit()
+	if limit.RequestsPerSecond <= 0 {
+		limit.RequestsPerSecond = defaults.RequestsPerSecond
+	}
+	if limit.Burst <= 0 {
+		limit.Burst = defaults.Burst
+	}
+	if limit.MaxInFlight <= 0 {
+		limit.MaxInFlight = defaults.MaxInFlight
+	}
+
+	s.rateLimit = limit
+	s.limiter = newTokenBucket(limit.RequestsPerSecond, limit.Burst)
+}
+
+// tokenBucket is a token-bucket limiter safe for use by many goroutines.
+// Tokens refill continuously at rate per second up to burst
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	// heldUntil blocks every caller after the site asks us to slow down
+	heldUntil time.Time
+}
+
+// newTokenBucket creates a bucket that starts full
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is cancelled
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	for {
+		delay := b.reserve()
+		if delay == 0 {
+			return nil
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a token if one is available and returns 0,
+// otherwise it returns how long until the next token is due
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(b.heldUntil) {
+		return b.heldUntil.Sub(now)
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// hold stops handing out tokens for d and empties the bucket,
+// so no burst follows once the hold ends
+func (b *tokenBucket) hold(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	until := time.Now().Add(d)
+	if until.After(b.heldUntil) {
+		b.heldUntil = until
+	}
+	b.tokens = 0
+	b.last = b.heldUntil
+}
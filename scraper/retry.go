@@ -0,0 +1,142 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// RetryPolicy controls how transient failures (429, 5xx, network errors)
+// are retried. Budget caps the total number of retries in one scrape,
+// so a site outage fails fast instead of retrying every film.
+// AttemptTimeout bounds each attempt; the waits between attempts are not
+// counted, and a Retry-After longer than MaxDelay is cut to MaxDelay
+type RetryPolicy struct {
+	MaxAttempts    int           `json:"max_attempts"`
+	BaseDelay      time.Duration `json:"base_delay"`
+	MaxDelay       time.Duration `json:"max_delay"`
+	AttemptTimeout time.Duration `json:"attempt_timeout"`
+	Budget         int           `json:"budget"`
+}
+
+// DefaultRetryPolicy retries each request up to 4 more times,
+// backing off from 1s up to a minute, with 30s per attempt and
+// 200 retries per run
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		BaseDelay:      time.Second,
+		MaxDelay:       time.Minute,
+		AttemptTimeout: 30 * time.Second,
+		Budget:         200,
+	}
+}
+
+// SetRetryPolicy replaces the scraper's retry policy.
+// Zero or negative fields fall back to the defaults
+func (s *Scraper) SetRetryPolicy(policy RetryPolicy) {
+	defaults := DefaultRetryPolicy()
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = defaults.MaxAttempts
+	}
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = defaults.BaseDelay
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = defaults.MaxDelay
+	}
+	if policy.AttemptTimeout <= 0 {
+		policy.AttemptTimeout = defaults.AttemptTimeout
+	}
+	if policy.Budget < 0 {
+		policy.Budget = defaults.Budget
+	}
+
+	s.retries = newRetrier(policy)
+}
+
+// retrier runs requests under a RetryPolicy and tracks the run's budget
+type retrier struct {
+	policy    RetryPolicy
+	remaining atomic.Int64
+}
+
+// newRetrier creates a retrier with a full budget
+func newRetrier(policy RetryPolicy) *retrier {
+	r := &retrier{policy: policy}
+	r.reset()
+	return r
+}
+
+// reset refills the retry budget at the start of a run
+func (r *retrier) reset() {
+	r.remaining.Store(int64(r.policy.Budget))
+}
+
+// do calls fetch for url until it succeeds, fails permanently, runs out of
+// attempts or budget, or ctx is cancelled. Each attempt gets its own
+// timeout, and one that times out is retried as a network error.
+// A rate-limited response also holds the shared limiter so other workers
+// back off too
+func (r *retrier) do(ctx context.Context, limiter *tokenBucket, url string, fetch func(context.Context) ([]byte, error)) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		body, err := r.attempt(ctx, url, fetch)
+		if err == nil {
+			return body, nil
+		}
+		if ctx.Err() != nil || !isRetryable(err) || attempt >= r.policy.MaxAttempts {
+			return nil, err
+		}
+		if r.remaining.Add(-1) < 0 {
+			log.Printf("Retry budget exhausted, giving up: %v\n", err)
+			return nil, err
+		}
+
+		delay := r.backoff(attempt)
+		var fe *FetchError
+		if errors.As(err, &fe) && fe.RetryAfter > 0 {
+			delay = min(fe.RetryAfter, r.policy.MaxDelay)
+		}
+		if errors.Is(err, ErrRateLimited) {
+			limiter.hold(delay)
+		}
+
+		log.Printf("Retrying in %v (attempt %d/%d): %v\n", delay.Round(time.Millisecond), attempt+1, r.policy.MaxAttempts, err)
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt runs a single fetch under the policy's attempt timeout
+func (r *retrier) attempt(ctx context.Context, url string, fetch func(context.Context) ([]byte, error)) ([]byte, error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, r.policy.AttemptTimeout)
+	defer cancel()
+
+	body, err := fetch(attemptCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		return nil, &FetchError{URL: url, Kind: ErrNetwork, Err: fmt.Errorf("attempt timed out after %v", r.policy.AttemptTimeout)}
+	}
+	return body, err
+}
+
+// backoff returns the jittered exponential delay before the given retry:
+// a random duration between half and all of BaseDelay * 2^(attempt-1),
+// capped at MaxDelay
+func (r *retrier) backoff(attempt int) time.Duration {
+	delay := r.policy.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > r.policy.MaxDelay {
+		delay = r.policy.MaxDelay
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
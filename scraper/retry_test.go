@@ -0,0 +1,162 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer answers the first failures requests with status and
+// retryAfter, then serves a films page. calls counts every request
+func flakyServer(t *testing.T, failures int, status int, retryAfter string, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(calls.Add(1)) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`<html><body><ul><li class="griditem" data-film-name="Paterson" data-film-link="/film/paterson/"></li></ul></body></html>`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// testRetrier creates a retrier with short delays and a fresh limiter
+func testRetrier(policy RetryPolicy) (*retrier, *tokenBucket) {
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = 3
+	}
+	if policy.BaseDelay == 0 {
+		policy.BaseDelay = time.Millisecond
+	}
+	if policy.MaxDelay == 0 {
+		policy.MaxDelay = 10 * time.Millisecond
+	}
+	if policy.AttemptTimeout == 0 {
+		policy.AttemptTimeout = time.Second
+	}
+	if policy.Budget == 0 {
+		policy.Budget = 10
+	}
+	return newRetrier(policy), newTokenBucket(1000, 100)
+}
+
+func TestRetrierRetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := flakyServer(t, 2, http.StatusServiceUnavailable, "", &calls)
+	r, limiter := testRetrier(RetryPolicy{})
+	fetcher := NewHTTPFetcher()
+
+	body, err := r.do(context.Background(), limiter, srv.URL, func(ctx context.Context) ([]byte, error) {
+		return fetcher.Fetch(ctx, srv.URL)
+	})
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	if len(body) == 0 {
+		t.Error("empty body")
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
+
+func TestRetrierGivesUpOnNotFound(t *testing.T) {
+	var calls atomic.Int32
+	srv := flakyServer(t, 5, http.StatusNotFound, "", &calls)
+	r, limiter := testRetrier(RetryPolicy{})
+	fetcher := NewHTTPFetcher()
+
+	_, err := r.do(context.Background(), limiter, srv.URL, func(ctx context.Context) ([]byte, error) {
+		return fetcher.Fetch(ctx, srv.URL)
+	})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestRetrierCapsRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	srv := flakyServer(t, 1, http.StatusTooManyRequests, "3600", &calls)
+	r, limiter := testRetrier(RetryPolicy{MaxDelay: 50 * time.Millisecond})
+	fetcher := NewHTTPFetcher()
+
+	start := time.Now()
+	_, err := r.do(context.Background(), limiter, srv.URL, func(ctx context.Context) ([]byte, error) {
+		return fetcher.Fetch(ctx, srv.URL)
+	})
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("took %v, Retry-After was not capped at MaxDelay", elapsed)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
+func TestRetrierTimesOutAttempts(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first request hangs until the client gives up
+		if calls.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(srv.Close)
+	r, limiter := testRetrier(RetryPolicy{AttemptTimeout: 100 * time.Millisecond})
+	fetcher := NewHTTPFetcher()
+
+	body, err := r.do(context.Background(), limiter, srv.URL, func(ctx context.Context) ([]byte, error) {
+		return fetcher.Fetch(ctx, srv.URL)
+	})
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	if string(body) != "ok" {
+		t.Errorf("body = %q, want ok", body)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
+// A Retry-After wait longer than colly's default 10s request timeout must
+// not fail the page, since the waits happen inside the collector's transport
+func TestCollectorOutlivesRetryAfter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits out an 11s Retry-After")
+	}
+
+	var calls atomic.Int32
+	srv := flakyServer(t, 1, http.StatusTooManyRequests, "11", &calls)
+	s := NewScraper(nil, NewHTTPFetcher())
+	s.SetRateLimit(RateLimit{RequestsPerSecond: 1000, Burst: 100})
+
+	movies, _, err := s.scrapeFilmsPage(context.Background(), srv.URL+"/bob/films/page/1/")
+	if err != nil {
+		t.Fatalf("scrapeFilmsPage: %v", err)
+	}
+	if len(movies) != 1 {
+		t.Errorf("got %d movies, want 1", len(movies))
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
@@ -0,0 +1,245 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"letterboxd-tracker/database"
+	"log"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+)
+
+// reviewItem is a review read from the reviews list. Long reviews are cut
+// short there; truncated is set when the full text has to be fetched from
+// fullTextURL (or the review page when the list gives none)
+type reviewItem struct {
+	review      database.Review
+	truncated   bool
+	fullTextURL string
+}
+
+// syncReviews scrapes every page of the user's reviews and saves the
+// reviews of films in the database, fetching the full text of truncated
+// ones. Reviews are newest first, so in incremental mode paging stops
+// after a run of known reviews.
+// Returns how many reviews were saved and how many failed
+func (s *Scraper) syncReviews(ctx context.Context, username string, opts SyncOptions) (saved, failed int, err error) {
+	var items []reviewItem
+	pageNum := 1
+	knownStreak := 0
+
+	for {
+		url := fmt.Sprintf("https://letterboxd.com/%s/films/reviews/page/%d/", username, pageNum)
+		log.Printf("Scraping reviews page %d: %s\n", pageNum, url)
+
+		pageItems, hasNext, err := s.scrapeReviewsPage(ctx, url)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to scrape reviews page %d: %w", pageNum, err)
+		}
+
+		reachedKnown := false
+		if opts.Incremental {
+			pageItems, reachedKnown, err = takeUntilKnown(pageItems, &knownStreak, opts.KnownStreak,
+				func(item reviewItem) (bool, error) { return s.db.ReviewExists(item.review.Key) })
+			if err != nil {
+				return 0, 0, err
+			}
+		}
+		items = append(items, pageItems...)
+		s.report(Progress{Phase: PhaseReviews, Page: pageNum, Total: len(items)})
+
+		if reachedKnown || !hasNext {
+			break
+		}
+
+		pageNum++
+	}
+
+	for i, item := range items {
+		if err := ctx.Err(); err != nil {
+			return saved, failed, err
+		}
+		review := item.review
+
+		exists, err := s.db.MovieExists(review.LetterboxdID)
+		if err != nil {
+			log.Printf("Error checking if movie exists: %v\n", err)
+			failed++
+			continue
+		}
+		if !exists {
+			log.Printf("Skipping review of unknown film: %s\n", review.LetterboxdID)
+			continue
+		}
+
+		if item.truncated {
+			text, err := s.scrapeReviewText(ctx, item)
+			if err != nil {
+				if ctx.Err() != nil {
+					return saved, failed, ctx.Err()
+				}
+				log.Printf("Error scraping full review of %s (%s): %v\n", review.LetterboxdID, errorKind(err), err)
+				failed++
+				continue
+			}
+			review.Text = text
+		}
+
+		if err := s.db.SaveReview(review); err != nil {
+			log.Printf("Error saving review of %s: %v\n", review.LetterboxdID, err)
+			failed++
+			continue
+		}
+		saved++
+
+		s.report(Progress{Phase: PhaseReviews, Page: pageNum, Current: i + 1, Total: len(items), Title: review.Title})
+	}
+
+	return saved, failed, nil
+}
+
+// scrapeReviewsPage scrapes a single page of the user's reviews
+func (s *Scraper) scrapeReviewsPage(ctx context.Context, url string) ([]reviewItem, bool, error) {
+	var items []reviewItem
+	hasNext := false
+
+	c := s.newCollector(ctx)
+
+	// Handle errors
+	c.OnError(func(_ *colly.Response, err error) {
+		log.Printf("Error scraping reviews: %v\n", err)
+	})
+
+	// One entry per review
+	c.OnHTML("li.film-detail, article.production-viewing", func(e *colly.HTMLElement) {
+		// The title links to the review itself
+		// e.g. /username/film/paterson/ or /username/film/paterson/1/
+		reviewLink := e.ChildAttr("h2 a", "href")
+		if reviewLink == "" {
+			reviewLink = e.ChildAttr(".film-detail-content h2 a, header a[href*='/film/']", "href")
+		}
+
+		filmLink := e.ChildAttr("[data-item-link]", "data-item-link")
+		if filmLink == "" {
+			filmLink = e.ChildAttr("[data-film-link]", "data-film-link")
+		}
+		if filmLink == "" {
+			filmLink = extractReviewFilmLink(reviewLink)
+		}
+		letterboxdID := extractLetterboxdID(filmLink)
+		if letterboxdID == "" {
+			return
+		}
+
+		reviewURL := reviewLink
+		if reviewURL != "" && !strings.HasPrefix(reviewURL, "http") {
+			reviewURL = "https://letterboxd.com" + reviewURL
+		}
+
+		// Reviews logged on a date link to that diary day
+		watched, _ := parseDiaryDate(e.ChildAttr("a[href*='/for/']", "href"))
+
+		var rating float64
+		if ratingText := strings.TrimSpace(e.ChildText("span.rating")); ratingText != "" {
+			if r, err := symbolToRating(ratingText); err == nil {
+				rating = r
+			}
+		}
+
+		likeCount := parseInt(strings.ReplaceAll(e.ChildAttr("[data-count]", "data-count"), ",", ""))
+		if likeCount == 0 {
+			if fields := strings.Fields(e.ChildText(".like-link-target .count, .likes-count")); len(fields) > 0 {
+				likeCount = parseInt(strings.ReplaceAll(fields[0], ",", ""))
+			}
+		}
+
+		// Work on a copy so the "more" link can be dropped from the text
+		body := e.DOM.Find(".body-text").First().Clone()
+		more := body.Find("a.reveal, .js-reveal, a.more-link")
+		truncated := more.Length() > 0
+		more.Remove()
+		body.Find(".contains-spoilers").Remove()
+
+		fullTextURL, _ := body.Attr("data-full-text-url")
+		if fullTextURL != "" {
+			truncated = true
+			if !strings.HasPrefix(fullTextURL, "http") {
+				fullTextURL = "https://letterboxd.com" + fullTextURL
+			}
+		}
+
+		items = append(items, reviewItem{
+			review: database.Review{
+				Key:          database.ReviewKey(letterboxdID, watched),
+				LetterboxdID: letterboxdID,
+				Title:        strings.TrimSpace(e.ChildText("h2 a")),
+				URL:          reviewURL,
+				WatchedDate:  watched,
+				Rating:       rating,
+				Text:         reviewText(body),
+				LikeCount:    likeCount,
+				Spoiler:      e.DOM.Find(".contains-spoilers").Length() > 0,
+			},
+			truncated:   truncated,
+			fullTextURL: fullTextURL,
+		})
+	})
+
+	// Check for next page
+	c.OnHTML("a[class=next]", func(e *colly.HTMLElement) {
+		hasNext = true
+	})
+
+	err := c.Visit(url)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to visit page: %w", err)
+	}
+
+	return items, hasNext, nil
+}
+
+// scrapeReviewText fetches the full text of a truncated review, from its
+// full-text fragment or else from the review page
+func (s *Scraper) scrapeReviewText(ctx context.Context, item reviewItem) (string, error) {
+	visitURL, selector := item.fullTextURL, "body"
+	if visitURL == "" {
+		visitURL, selector = item.review.URL, ".review .body-text, .js-review-body"
+	}
+	if visitURL == "" {
+		return "", fmt.Errorf("%w: review of %s has no link to its full text", ErrParse, item.review.LetterboxdID)
+	}
+
+	var text string
+	c := s.newCollector(ctx)
+	c.OnHTML(selector, func(e *colly.HTMLElement) {
+		if text == "" {
+			text = reviewText(e.DOM)
+		}
+	})
+
+	if err := c.Visit(visitURL); err != nil {
+		return "", fmt.Errorf("failed to visit review: %w", err)
+	}
+	if text == "" {
+		return "", fmt.Errorf("%w: no review text in %s", ErrParse, visitURL)
+	}
+
+	return text, nil
+}
+
+// reviewText joins the paragraphs of a review body with blank lines
+func reviewText(body *goquery.Selection) string {
+	var paragraphs []string
+	body.Find("p").Each(func(_ int, p *goquery.Selection) {
+		if text := strings.TrimSpace(p.Text()); text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+	})
+
+	if len(paragraphs) == 0 {
+		return strings.TrimSpace(body.Text())
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
@@ -0,0 +1,90 @@
+package scraper
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestScrapeReviewsPage(t *testing.T) {
+	s := newFixtureScraper(t)
+
+	items, hasNext, err := s.scrapeReviewsPage(context.Background(), "https://letterboxd.com/bob/films/reviews/page/1/")
+	if err != nil {
+		t.Fatalf("scrapeReviewsPage: %v", err)
+	}
+	if hasNext {
+		t.Error("hasNext = true on the last page")
+	}
+	if len(items) != 3 {
+		t.Fatalf("got %d reviews, want 3", len(items))
+	}
+
+	// Dated, truncated with a full-text link, spoiler warning
+	dated := items[0]
+	if dated.review.Key != "/film/paterson:2024-03-15" || dated.review.LetterboxdID != "/film/paterson" {
+		t.Errorf("dated review = %q of %q", dated.review.Key, dated.review.LetterboxdID)
+	}
+	if !dated.review.WatchedDate.Equal(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("watched = %v, want 2024-03-15", dated.review.WatchedDate)
+	}
+	if dated.review.Rating != 4.5 || dated.review.LikeCount != 1204 || !dated.review.Spoiler {
+		t.Errorf("rating %v, likes %d, spoiler %v, want 4.5, 1204, true",
+			dated.review.Rating, dated.review.LikeCount, dated.review.Spoiler)
+	}
+	if !dated.truncated || dated.fullTextURL != "https://letterboxd.com/s/full-text/viewing/111/" {
+		t.Errorf("truncated %v, full text %q", dated.truncated, dated.fullTextURL)
+	}
+	if want := "A poem of a film.\n\nThe bus driver…"; dated.review.Text != want {
+		t.Errorf("text = %q, want %q", dated.review.Text, want)
+	}
+
+	// Undated, complete, keyed by its film
+	undated := items[1]
+	if undated.review.Key != "/film/paterson:undated" || !undated.review.WatchedDate.IsZero() {
+		t.Errorf("undated review = %q watched %v", undated.review.Key, undated.review.WatchedDate)
+	}
+	if undated.review.URL != "https://letterboxd.com/bob/film/paterson/1/" {
+		t.Errorf("url = %q", undated.review.URL)
+	}
+	if undated.truncated || undated.review.Spoiler || undated.review.LikeCount != 3 {
+		t.Errorf("truncated %v, spoiler %v, likes %d, want false, false, 3",
+			undated.truncated, undated.review.Spoiler, undated.review.LikeCount)
+	}
+	if undated.review.Text != "Second look, no date." {
+		t.Errorf("text = %q", undated.review.Text)
+	}
+
+	// Truncated without a full-text link
+	more := items[2]
+	if more.review.LetterboxdID != "/film/amelie" || !more.truncated || more.fullTextURL != "" {
+		t.Errorf("review of %q truncated %v full text %q", more.review.LetterboxdID, more.truncated, more.fullTextURL)
+	}
+}
+
+func TestScrapeReviewText(t *testing.T) {
+	s := newFixtureScraper(t)
+	items, _, err := s.scrapeReviewsPage(context.Background(), "https://letterboxd.com/bob/films/reviews/page/1/")
+	if err != nil {
+		t.Fatalf("scrapeReviewsPage: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		item reviewItem
+		want string
+	}{
+		{"full-text fragment", items[0], "A poem of a film.\n\nThe bus driver writes poems, and it ends with a blank page."},
+		{"review page", items[1], "Full second look.\n\nPara two."},
+	}
+	for _, tt := range tests {
+		got, err := s.scrapeReviewText(context.Background(), tt.item)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: text = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,124 +1,400 @@
 package scraper
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"letterboxd-tracker/database"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/gocolly/colly/v2"
 )
 
-// Scraper orchestrates the two-pass scraping process
+// Scraper orchestrates the multi-pass scraping process
 type Scraper struct {
-	db *database.MovieDB
+	db         *database.MovieDB
+	fetcher    Fetcher
+	onProgress ProgressFunc
+	rateLimit  RateLimit
+	limiter    *tokenBucket
+	retries    *retrier
 }
 
-// NewScraper creates a new Scraper instance
-func NewScraper(db *database.MovieDB) *Scraper {
-	return &Scraper{db: db}
+// NewScraper creates a new Scraper instance that loads pages through fetcher.
+// A nil fetcher defaults to fetching live from letterboxd.com
+func NewScraper(db *database.MovieDB, fetcher Fetcher) *Scraper {
+	if fetcher == nil {
+		fetcher = NewHTTPFetcher()
+	}
+	s := &Scraper{db: db, fetcher: fetcher}
+	s.SetRateLimit(DefaultRateLimit())
+	s.SetRetryPolicy(DefaultRetryPolicy())
+	return s
+}
+
+// ScrapeUser performs a full multi-pass scrape of a Letterboxd user's films
+func (s *Scraper) ScrapeUser(ctx context.Context, username string) error {
+	_, err := s.Sync(ctx, username, SyncOptions{})
+	return err
 }
 
-// ScrapeUser performs two-pass scraping of a Letterboxd user's films
+// Sync performs multi-pass scraping of a Letterboxd user's films
 // Pass 1: Collects all basic movie info from films list pages
-// Pass 2: For each new movie, scrapes detailed info from detail pages
-func (s *Scraper) ScrapeUser(username string) error {
-	log.Printf("Starting scrape for user: %s\n", username)
+// Pass 2: Scrapes detail pages of new movies (and refreshes known ones in refresh mode)
+// Pass 3: Collects dated viewings from the user's diary
+// Pass 4: Marks the films on the user's likes list as liked
+// Pass 5: Replaces the stored watchlist, scraping details of films new to it
+// Pass 6: Syncs the user's lists and the films on each changed list
+// Pass 7: Saves the user's reviews of films in the database, with their full text
+// Cancelling ctx stops the scrape between requests; films already saved are kept.
+// A sync that finishes without errors is recorded as the user's last sync
+func (s *Scraper) Sync(ctx context.Context, username string, opts SyncOptions) (Result, error) {
+	if opts.Incremental && opts.KnownStreak <= 0 {
+		opts.KnownStreak = DefaultKnownStreak
+	}
+	if opts.Refresh {
+		// A refresh has to see every film to re-read its rating
+		opts.Incremental = false
+		if opts.MaxAge <= 0 {
+			opts.MaxAge = DefaultMaxAge
+		}
+	}
+
+	log.Printf("Starting scrape for user: %s (incremental: %v, refresh: %v)\n", username, opts.Incremental, opts.Refresh)
+	s.retries.reset()
+	startedAt := time.Now()
 
 	// Pass 1: Collect basic movie info
-	basicMovies, err := s.scrapeFilmsList(username)
+	basicMovies, err := s.scrapeFilmsList(ctx, username, opts)
 	if err != nil {
-		return fmt.Errorf("pass 1 failed: %w", err)
+		return Result{}, fmt.Errorf("pass 1 failed: %w", err)
 	}
 
 	log.Printf("Pass 1 complete: Found %d movies\n", len(basicMovies))
 
-	// Pass 2: Scrape details for new movies only
-	var totalScraped, skipped, failed int
-	for i, movie := range basicMovies {
-		// Check if movie already exists
-		exists, err := s.db.MovieExists(movie.LetterboxdID)
-		if err != nil {
-			log.Printf("Error checking if movie exists: %v\n", err)
-			failed++
-			continue
+	// Pass 2: Scrape details for new (or stale) movies
+	result, err := s.scrapeDetails(ctx, basicMovies, opts)
+	if err != nil {
+		return result, fmt.Errorf("scrape cancelled: %w", err)
+	}
+
+	log.Printf("Pass 2 complete: Scraped %d, Refreshed %d, Skipped %d, Failed %d\n", result.Scraped, result.Refreshed, result.Skipped, result.Failed)
+
+	// Pass 3: Record diary viewings for films now in the database
+	viewings, err := s.scrapeDiary(ctx, username, opts)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return result, fmt.Errorf("scrape cancelled: %w", ctxErr)
 		}
+		log.Printf("Error scraping diary: %v\n", err)
+		result.Failed++
+	} else {
+		saved, viewingsFailed := s.saveViewings(viewings)
+		result.Viewings = saved
+		result.Failed += viewingsFailed
+		log.Printf("Pass 3 complete: Saved %d viewings, Failed %d\n", saved, viewingsFailed)
+	}
 
-		if exists {
-			log.Printf("[%d/%d] Skipping existing movie: %s\n", i+1, len(basicMovies), movie.Title)
-			skipped++
-			continue
+	// Pass 4: Record liked films
+	liked, err := s.syncLikes(ctx, username, opts)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return result, fmt.Errorf("scrape cancelled: %w", ctxErr)
 		}
+		log.Printf("Error scraping likes: %v\n", err)
+		result.Failed++
+	} else {
+		result.Liked = liked
+		log.Printf("Pass 4 complete: Found %d liked films\n", liked)
+	}
 
-		// Scrape details for this movie
-		log.Printf("[%d/%d] Scraping details for: %s\n", i+1, len(basicMovies), movie.Title)
-		err = s.scrapeMovieDetails(&movie)
-		if err != nil {
-			log.Printf("Error scraping details for %s: %v\n", movie.Title, err)
-			failed++
-			continue
+	// Pass 5: Replace the watchlist
+	watchlist, watchlistFailed, err := s.syncWatchlist(ctx, username, opts)
+	result.Failed += watchlistFailed
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return result, fmt.Errorf("scrape cancelled: %w", ctxErr)
+		}
+		log.Printf("Error scraping watchlist: %v\n", err)
+		result.Failed++
+	} else {
+		result.Watchlist = watchlist
+		log.Printf("Pass 5 complete: Saved %d watchlist films, Failed %d\n", watchlist, watchlistFailed)
+	}
+
+	// Pass 6: Sync lists
+	lists, listsFailed, err := s.syncLists(ctx, username, opts)
+	result.Failed += listsFailed
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return result, fmt.Errorf("scrape cancelled: %w", ctxErr)
+		}
+		log.Printf("Error scraping lists: %v\n", err)
+		result.Failed++
+	} else {
+		result.Lists = lists
+		log.Printf("Pass 6 complete: Synced %d lists, Failed %d\n", lists, listsFailed)
+	}
+
+	// Pass 7: Record reviews
+	reviews, reviewsFailed, err := s.syncReviews(ctx, username, opts)
+	result.Failed += reviewsFailed
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return result, fmt.Errorf("scrape cancelled: %w", ctxErr)
+		}
+		log.Printf("Error scraping reviews: %v\n", err)
+		result.Failed++
+	} else {
+		result.Reviews = reviews
+		log.Printf("Pass 7 complete: Saved %d reviews, Failed %d\n", reviews, reviewsFailed)
+	}
+
+	s.report(Progress{
+		Phase:     PhaseDone,
+		Current:   len(basicMovies),
+		Total:     len(basicMovies),
+		Scraped:   result.Scraped,
+		Refreshed: result.Refreshed,
+		Skipped:   result.Skipped,
+		Failed:    result.Failed,
+	})
+
+	if result.Failed > 0 {
+		return result, fmt.Errorf("scraping completed with %d errors", result.Failed)
+	}
+
+	if err := s.db.RecordSync(username, startedAt, !opts.Incremental); err != nil {
+		log.Printf("Error recording sync: %v\n", err)
+	}
+
+	return result, nil
+}
+
+// scrapeDetails runs pass 2 on a bounded pool of workers.
+// Each new film's detail page is scraped and saved. Films already in the
+// database are skipped, unless refreshing: then their rating is updated
+// from the list page and stale details are re-scraped and upserted.
+// Requests are paced by the shared rate limiter.
+// Only cancellation of ctx is returned as an error, per-film failures are counted
+func (s *Scraper) scrapeDetails(ctx context.Context, movies []database.Movie, opts SyncOptions) (Result, error) {
+	var result Result
+	progress := Progress{Phase: PhaseDetails, Total: len(movies)}
+	var mu sync.Mutex
+
+	// record updates the shared counters and reports them in order
+	record := func(title string, outcome *int, changes []database.FieldChange) {
+		mu.Lock()
+		defer mu.Unlock()
+		*outcome++
+		result.Changes = append(result.Changes, changes...)
+		progress.Current++
+		progress.Title = title
+		progress.Scraped = result.Scraped
+		progress.Refreshed = result.Refreshed
+		progress.Skipped = result.Skipped
+		progress.Failed = result.Failed
+		s.report(progress)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < s.rateLimit.MaxInFlight; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				movie := movies[i]
+
+				// Check if movie already exists
+				existing, exists, err := s.db.GetMovie(movie.LetterboxdID)
+				if err != nil {
+					log.Printf("Error checking if movie exists: %v\n", err)
+					record(movie.Title, &result.Failed, nil)
+					continue
+				}
+
+				if exists && !opts.Refresh && existing.Rating == movie.Rating && existing.Liked == movie.Liked {
+					log.Printf("[%d/%d] Skipping existing movie: %s\n", i+1, len(movies), movie.Title)
+					record(movie.Title, &result.Skipped, nil)
+					continue
+				}
+
+				// Outside a refresh, or with fresh details, only the rating and
+				// like from the list page are updated
+				if exists && (!opts.Refresh || time.Since(existing.DetailsScrapedAt) < opts.MaxAge) {
+					updated := existing
+					updated.Rating = movie.Rating
+					updated.Liked = movie.Liked
+					changes, err := s.db.UpsertMovie(updated)
+					if err != nil {
+						log.Printf("Error saving movie %s: %v\n", movie.Title, err)
+						record(movie.Title, &result.Failed, nil)
+						continue
+					}
+					if len(changes) == 0 {
+						record(movie.Title, &result.Skipped, nil)
+					} else {
+						record(movie.Title, &result.Refreshed, changes)
+					}
+					continue
+				}
+
+				// Scrape details for this movie
+				log.Printf("[%d/%d] Scraping details for: %s\n", i+1, len(movies), movie.Title)
+				err = s.scrapeMovieDetails(ctx, &movie)
+				if err != nil {
+					if ctx.Err() != nil {
+						continue
+					}
+					log.Printf("Error scraping details for %s (%s): %v\n", movie.Title, errorKind(err), err)
+					record(movie.Title, &result.Failed, nil)
+					continue
+				}
+				movie.DetailsScrapedAt = time.Now()
+
+				// A film created by an export import is still keyed by its
+				// boxd.it link; move it to the slug instead of adding a copy
+				if !exists {
+					importedID, found, err := s.db.FindImportedMovie(movie.LetterboxdURI, movie.Title, movie.Year)
+					if err == nil && found {
+						log.Printf("Matched imported film %s to %s\n", importedID, movie.LetterboxdID)
+						err = s.db.RekeyMovie(importedID, movie.LetterboxdID)
+						exists = err == nil
+					}
+					if err != nil {
+						log.Printf("Error matching imported film %s: %v\n", movie.Title, err)
+						record(movie.Title, &result.Failed, nil)
+						continue
+					}
+				}
+
+				if exists {
+					changes, err := s.db.UpsertMovie(movie)
+					if err != nil {
+						log.Printf("Error saving movie %s: %v\n", movie.Title, err)
+						record(movie.Title, &result.Failed, nil)
+						continue
+					}
+					record(movie.Title, &result.Refreshed, changes)
+					continue
+				}
+
+				// Add movie to database
+				err = s.db.AddMovie(movie)
+				if err != nil {
+					log.Printf("Error saving movie %s: %v\n", movie.Title, err)
+					record(movie.Title, &result.Failed, nil)
+					continue
+				}
+
+				record(movie.Title, &result.Scraped, nil)
+			}
+		}()
+	}
+
+dispatch:
+	for i := range movies {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
 		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
 
-		// Add movie to database
-		err = s.db.AddMovie(movie)
+	return result, nil
+}
+
+// saveViewings stores diary viewings, skipping films that are not in the database
+func (s *Scraper) saveViewings(viewings []database.Viewing) (saved, failed int) {
+	for _, viewing := range viewings {
+		exists, err := s.db.MovieExists(viewing.LetterboxdID)
 		if err != nil {
-			log.Printf("Error saving movie %s: %v\n", movie.Title, err)
+			log.Printf("Error checking if movie exists: %v\n", err)
 			failed++
 			continue
 		}
 
-		totalScraped++
-
-		// Rate limiting: 500ms between requests
-		time.Sleep(500 * time.Millisecond)
-	}
+		if !exists {
+			log.Printf("Skipping viewing of unknown film: %s\n", viewing.LetterboxdID)
+			continue
+		}
 
-	log.Printf("Pass 2 complete: Scraped %d, Skipped %d, Failed %d\n", totalScraped, skipped, failed)
+		if err := s.db.AddViewing(viewing); err != nil {
+			log.Printf("Error saving viewing of %s: %v\n", viewing.LetterboxdID, err)
+			failed++
+			continue
+		}
 
-	if failed > 0 {
-		return fmt.Errorf("scraping completed with %d errors", failed)
+		saved++
 	}
 
-	return nil
+	return saved, failed
 }
 
 // scrapeFilmsList scrapes the films list pages to get basic movie info
-// Follows pagination using the .next selector
-func (s *Scraper) scrapeFilmsList(username string) ([]database.Movie, error) {
+// Follows pagination using the .next selector. In incremental mode the
+// list is sorted newest first and paging stops after a run of known films
+func (s *Scraper) scrapeFilmsList(ctx context.Context, username string, opts SyncOptions) ([]database.Movie, error) {
 	var allMovies []database.Movie
 	pageNum := 1
+	knownStreak := 0
+
+	listPath := "films"
+	if opts.Incremental {
+		listPath = "films/by/date"
+	}
 
 	for {
-		url := fmt.Sprintf("https://www.letterboxd.com/%s/films/page/%d/", username, pageNum)
+		url := fmt.Sprintf("https://www.letterboxd.com/%s/%s/page/%d/", username, listPath, pageNum)
 		log.Printf("Scraping page %d: %s\n", pageNum, url)
 
-		pageMovies, hasNext, err := s.scrapeFilmsPage(url)
+		pageMovies, hasNext, err := s.scrapeFilmsPage(ctx, url)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scrape page %d: %w", pageNum, err)
 		}
 
+		reachedKnown := false
+		if opts.Incremental {
+			pageMovies, reachedKnown, err = takeUntilKnown(pageMovies, &knownStreak, opts.KnownStreak,
+				func(movie database.Movie) (bool, error) { return s.db.MovieExists(movie.LetterboxdID) })
+			if err != nil {
+				return nil, err
+			}
+		}
 		allMovies = append(allMovies, pageMovies...)
+		s.report(Progress{Phase: PhaseList, Page: pageNum, Total: len(allMovies)})
 
+		if reachedKnown {
+			log.Printf("Reached %d already-known films, stopping at page %d\n", knownStreak, pageNum)
+			break
+		}
 		if !hasNext {
 			break
 		}
 
 		pageNum++
-		time.Sleep(500 * time.Millisecond)
 	}
 
 	return allMovies, nil
 }
 
 // scrapeFilmsPage scrapes a single films list page
-func (s *Scraper) scrapeFilmsPage(url string) ([]database.Movie, bool, error) {
+func (s *Scraper) scrapeFilmsPage(ctx context.Context, url string) ([]database.Movie, bool, error) {
 	var movies []database.Movie
 	hasNext := false
 
-	c := colly.NewCollector()
+	c := s.newCollector(ctx)
 
 	// Handle errors
 	c.OnError(func(_ *colly.Response, err error) {
@@ -150,13 +426,17 @@ func (s *Scraper) scrapeFilmsPage(url string) ([]database.Movie, bool, error) {
 			}
 		}
 
+		// A heart next to the rating marks a liked film
+		liked := e.DOM.Find("p.poster-viewingdata .like, p.poster-viewingdata .icon-liked").Length() > 0
+
 		// Create basic movie entry
 		movie := database.Movie{
-			Title:        title,
-			LetterboxdID: extractLetterboxdID(letterboxdURL),
+			Title:         title,
+			LetterboxdID:  extractLetterboxdID(letterboxdURL),
 			LetterboxdURL: letterboxdURL,
-			Rating:       rating,
-			DateAdded:    time.Now(),
+			Rating:        rating,
+			Liked:         liked,
+			DateAdded:     time.Now(),
 		}
 
 		movies = append(movies, movie)
@@ -176,19 +456,38 @@ func (s *Scraper) scrapeFilmsPage(url string) ([]database.Movie, bool, error) {
 }
 
 // scrapeMovieDetails scrapes detailed information from a movie's detail page
-func (s *Scraper) scrapeMovieDetails(movie *database.Movie) error {
-	c := colly.NewCollector()
+func (s *Scraper) scrapeMovieDetails(ctx context.Context, movie *database.Movie) error {
+	c := s.newCollector(ctx)
 
 	c.OnError(func(_ *colly.Response, err error) {
 		log.Printf("Error scraping movie details: %v\n", err)
 	})
 
+	// Every film page carries a release date or LD+JSON block;
+	// a page with neither means Letterboxd changed its markup
+	foundFilm := false
+
 	// Extract year
 	c.OnHTML("span.releasedate", func(e *colly.HTMLElement) {
+		foundFilm = true
 		yearText := e.Text
 		movie.Year = parseInt(yearText)
 	})
 
+	// Extract the short boxd.it link, which the export uses to name films
+	c.OnHTML("link[rel=shortlink], input[value*='boxd.it/'], a[href*='boxd.it/']", func(e *colly.HTMLElement) {
+		if movie.LetterboxdURI != "" {
+			return
+		}
+		link := e.Attr("href")
+		if link == "" {
+			link = e.Attr("value")
+		}
+		if strings.Contains(link, "boxd.it/") {
+			movie.LetterboxdURI = strings.TrimSuffix(strings.TrimSpace(link), "/")
+		}
+	})
+
 	// Extract runtime
 	c.OnHTML("p.text-link.text-footer", func(e *colly.HTMLElement) {
 		runtimeText := e.Text
@@ -207,6 +506,7 @@ func (s *Scraper) scrapeMovieDetails(movie *database.Movie) error {
 
 	// Extract poster
 	c.OnHTML("script[type='application/ld+json']", func(e *colly.HTMLElement) {
+		foundFilm = true
 		raw := e.Text
 
 		// Some LD+JSON blocks may include HTML comment markers or CDATA wrappers
@@ -228,35 +528,63 @@ func (s *Scraper) scrapeMovieDetails(movie *database.Movie) error {
 		}
 	})
 
+	// Extract synopsis, falling back to the description meta tag
+	c.OnHTML("section.production-synopsis div.truncate, div.review.body-text", func(e *colly.HTMLElement) {
+		if movie.Synopsis == "" {
+			movie.Synopsis = strings.TrimSpace(e.Text)
+		}
+	})
 
-	// Extract directors (comma-separated)
-	c.OnHTML("span.directorlist a", func(e *colly.HTMLElement) {
-		director := e.Text
-		if director != "" {
-			if movie.Director == "" {
-				movie.Director = director
-			} else {
-				movie.Director += ", " + director
+	c.OnHTML("meta[property='og:description']", func(e *colly.HTMLElement) {
+		if movie.Synopsis == "" {
+			movie.Synopsis = strings.TrimSpace(e.Attr("content"))
+		}
+	})
+
+	// Extract external ids, carried on the body and in the footer links
+	c.OnHTML("body", func(e *colly.HTMLElement) {
+		if id := parseInt(e.Attr("data-tmdb-id")); id > 0 {
+			movie.TmdbID = id
+			movie.TmdbType = e.Attr("data-tmdb-type")
+			if movie.TmdbType == "" {
+				movie.TmdbType = "movie"
 			}
 		}
 	})
 
-	// Extract cast (top 30 actors, comma-separated), but stop at "Show All" link
-	castCount := 0
+	c.OnHTML("a[data-track-action='TMDB'], a[href*='themoviedb.org/']", func(e *colly.HTMLElement) {
+		if movie.TmdbID == 0 {
+			movie.TmdbType, movie.TmdbID = extractTmdbID(e.Attr("href"))
+		}
+	})
+
+	c.OnHTML("a[data-track-action='IMDb'], a[href*='imdb.com/title/']", func(e *colly.HTMLElement) {
+		if id := extractImdbID(e.Attr("href")); id != "" {
+			movie.ImdbID = id
+		}
+	})
+
+	// Credits are collected per role and joined onto the movie after the visit
+	var directors, cast, writers []database.Credit
+
+	// Extract directors from the header as a fallback for pages without a crew tab
+	c.OnHTML("span.directorlist a", func(e *colly.HTMLElement) {
+		if credit, ok := personCredit(e.DOM, database.RoleDirector, len(directors)); ok {
+			directors = append(directors, credit)
+		}
+	})
+
+	// Extract cast (top 30 actors), but stop at "Show All" link
 	c.OnHTML("div.cast-list a.text-slug", func(e *colly.HTMLElement) {
 		// Stop if this is the 'Show All' link (id or text)
 		if e.Attr("id") == "has-cast-overflow" || strings.Contains(strings.ToLower(e.Text), "show all") {
 			return
 		}
-		if castCount < 30 {
-			actor := strings.TrimSpace(e.Text)
-			if actor != "" {
-				if movie.Cast == "" {
-					movie.Cast = actor
-				} else {
-					movie.Cast += ", " + actor
-				}
-				castCount++
+		if len(cast) < 30 {
+			if credit, ok := personCredit(e.DOM, database.RoleActor, len(cast)); ok {
+				// The tooltip title holds the character name
+				credit.CharacterName = strings.TrimSpace(e.Attr("title"))
+				cast = append(cast, credit)
 			}
 		}
 	})
@@ -268,19 +596,8 @@ func (s *Scraper) scrapeMovieDetails(movie *database.Movie) error {
 			heading := strings.TrimSpace(h.Text)
 			// Look specifically for the Directors heading; exclude assistant or original variants
 			if strings.Contains(heading, "Director") && !strings.Contains(heading, "Assistant") && !strings.Contains(heading, "Original") {
-				nextDiv := h.DOM.Next()
-				var directors []string
-				nextDiv.Find("a.text-slug").Each(func(_ int, sel *goquery.Selection) {
-					if len(directors) >= 5 {
-						return
-					}
-					name := strings.TrimSpace(sel.Text())
-					if name != "" {
-						directors = append(directors, name)
-					}
-				})
-				if len(directors) > 0 {
-					movie.Director = strings.Join(directors, ", ")
+				if found := crewCredits(h.DOM.Next(), database.RoleDirector, 5); len(found) > 0 {
+					directors = found
 				}
 			}
 		})
@@ -291,20 +608,36 @@ func (s *Scraper) scrapeMovieDetails(movie *database.Movie) error {
 		e.ForEach("h3", func(_ int, h *colly.HTMLElement) {
 			heading := strings.TrimSpace(h.Text)
 			if strings.Contains(heading, "Writer") && !strings.Contains(heading, "Original") && !strings.Contains(heading, "Story") && !strings.Contains(heading, "Screenplay") {
-				nextDiv := h.DOM.Next()
-				var writers []string
-				nextDiv.Find("a.text-slug").Each(func(_ int, sel *goquery.Selection) {
-					if len(writers) >= 5 {
-						return
-					}
-					name := strings.TrimSpace(sel.Text())
-					if name != "" {
-						writers = append(writers, name)
-					}
-				})
-				if len(writers) > 0 {
-					movie.Writers = strings.Join(writers, ", ")
+				if found := crewCredits(h.DOM.Next(), database.RoleWriter, 5); len(found) > 0 {
+					writers = found
+				}
+			}
+		})
+	})
+
+	// Classifications from the genres and details tabs, matched by heading
+	var genres, countries, languages, studios, themes []database.Term
+	var primaryLanguage *database.Term
+
+	c.OnHTML("div#tab-genres, div#tab-details", func(e *colly.HTMLElement) {
+		e.ForEach("h3", func(_ int, h *colly.HTMLElement) {
+			heading := strings.ToLower(strings.TrimSpace(h.Text))
+			list := h.DOM.Next()
+			switch {
+			case strings.HasPrefix(heading, "genre"):
+				genres = termLinks(list, "/films/genre/")
+			case strings.HasPrefix(heading, "theme"):
+				themes = termLinks(list, "/films/theme/", "/films/mini-theme/")
+			case strings.HasPrefix(heading, "countr"):
+				countries = termLinks(list, "/films/country/")
+			case strings.HasPrefix(heading, "studio"):
+				studios = termLinks(list, "/studio/")
+			case strings.HasPrefix(heading, "primary language"), heading == "language":
+				if found := termLinks(list, "/films/language/"); len(found) > 0 {
+					primaryLanguage = &found[0]
 				}
+			case strings.Contains(heading, "language"):
+				languages = termLinks(list, "/films/language/")
 			}
 		})
 	})
@@ -320,5 +653,83 @@ func (s *Scraper) scrapeMovieDetails(movie *database.Movie) error {
 		return fmt.Errorf("failed to visit movie page: %w", err)
 	}
 
+	if !foundFilm {
+		return fmt.Errorf("%w: %s does not look like a film page", ErrParse, visitURL)
+	}
+
+	movie.Director = joinCreditNames(directors)
+	movie.Cast = joinCreditNames(cast)
+	movie.Writers = joinCreditNames(writers)
+
+	movie.Credits = make([]database.Credit, 0, len(directors)+len(cast)+len(writers))
+	movie.Credits = append(movie.Credits, directors...)
+	movie.Credits = append(movie.Credits, cast...)
+	movie.Credits = append(movie.Credits, writers...)
+
+	// Pages listing only "Languages" have no primary one; take the first
+	if primaryLanguage == nil && len(languages) > 0 {
+		primaryLanguage = &languages[0]
+	}
+	movie.Genres = genres
+	movie.Countries = countries
+	movie.PrimaryLanguage = primaryLanguage
+	movie.Languages = languages
+	movie.Studios = studios
+	movie.Themes = themes
+
 	return nil
 }
+
+// crewCredits collects up to limit people from a crew tab section
+func crewCredits(section *goquery.Selection, role string, limit int) []database.Credit {
+	var credits []database.Credit
+	section.Find("a.text-slug").Each(func(_ int, sel *goquery.Selection) {
+		if len(credits) >= limit {
+			return
+		}
+		if credit, ok := personCredit(sel, role, len(credits)); ok {
+			credits = append(credits, credit)
+		}
+	})
+	return credits
+}
+
+// personCredit builds a credit from a person link, e.g.
+// <a href="/director/jim-jarmusch/">Jim Jarmusch</a>
+func personCredit(sel *goquery.Selection, role string, order int) (database.Credit, bool) {
+	name := strings.TrimSpace(sel.Text())
+	slug := extractPersonSlug(sel.AttrOr("href", ""))
+	if name == "" || slug == "" {
+		return database.Credit{}, false
+	}
+	return database.Credit{
+		PersonSlug:   slug,
+		Name:         name,
+		Role:         role,
+		BillingOrder: order,
+	}, true
+}
+
+// termLinks collects the classification links of a details or genres tab
+// section, skipping links such as "Show All…" that match none of the prefixes
+func termLinks(section *goquery.Selection, prefixes ...string) []database.Term {
+	var terms []database.Term
+	section.Find("a").Each(func(_ int, sel *goquery.Selection) {
+		name := strings.TrimSpace(sel.Text())
+		slug := extractTermSlug(sel.AttrOr("href", ""), prefixes...)
+		if name == "" || slug == "" {
+			return
+		}
+		terms = append(terms, database.Term{Slug: slug, Name: name})
+	})
+	return terms
+}
+
+// joinCreditNames joins credit names into the legacy comma-separated form
+func joinCreditNames(credits []database.Credit) string {
+	names := make([]string, 0, len(credits))
+	for _, credit := range credits {
+		names = append(names, credit.Name)
+	}
+	return strings.Join(names, ", ")
+}
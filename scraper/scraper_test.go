@@ -0,0 +1,146 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"letterboxd-tracker/database"
+	"reflect"
+	"testing"
+)
+
+// newFixtureScraper creates a scraper that serves pages from testdata
+// without a database or rate limiting
+func newFixtureScraper(t *testing.T) *Scraper {
+	t.Helper()
+	s := NewScraper(nil, NewReplayFetcher("testdata"))
+	s.SetRateLimit(RateLimit{RequestsPerSecond: 1000, Burst: 100})
+	return s
+}
+
+func TestScrapeFilmsPage(t *testing.T) {
+	s := newFixtureScraper(t)
+
+	movies, hasNext, err := s.scrapeFilmsPage(context.Background(), "https://letterboxd.com/bob/films/page/1/")
+	if err != nil {
+		t.Fatalf("scrapeFilmsPage: %v", err)
+	}
+	if hasNext {
+		t.Error("hasNext = true on the last page")
+	}
+	if len(movies) != 2 {
+		t.Fatalf("got %d movies, want 2", len(movies))
+	}
+
+	tests := []struct {
+		id     string
+		title  string
+		rating float64
+		liked  bool
+	}{
+		{"/film/paterson", "Paterson", 4.5, true},
+		{"/film/ghost", "Ghost", 0, false},
+	}
+	for i, tt := range tests {
+		m := movies[i]
+		if m.LetterboxdID != tt.id || m.Title != tt.title || m.Rating != tt.rating || m.Liked != tt.liked {
+			t.Errorf("movie %d = %q %q rating %v liked %v, want %q %q rating %v liked %v",
+				i, m.LetterboxdID, m.Title, m.Rating, m.Liked, tt.id, tt.title, tt.rating, tt.liked)
+		}
+	}
+}
+
+func TestScrapeFilmsPageWatchlist(t *testing.T) {
+	s := newFixtureScraper(t)
+
+	movies, hasNext, err := s.scrapeFilmsPage(context.Background(), "https://letterboxd.com/bob/watchlist/page/1/")
+	if err != nil {
+		t.Fatalf("scrapeFilmsPage: %v", err)
+	}
+	if !hasNext {
+		t.Error("hasNext = false, want true")
+	}
+
+	var ids []string
+	for _, m := range movies {
+		ids = append(ids, m.LetterboxdID)
+	}
+	if want := []string{"/film/amelie", "/film/paterson"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+}
+
+func TestScrapeFilmsPageMissing(t *testing.T) {
+	s := newFixtureScraper(t)
+
+	_, _, err := s.scrapeFilmsPage(context.Background(), "https://letterboxd.com/nobody/watchlist/page/1/")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestScrapeMovieDetails(t *testing.T) {
+	s := newFixtureScraper(t)
+
+	movie := database.Movie{LetterboxdID: "/film/amelie", LetterboxdURL: "/film/amelie/"}
+	if err := s.scrapeMovieDetails(context.Background(), &movie); err != nil {
+		t.Fatalf("scrapeMovieDetails: %v", err)
+	}
+
+	if movie.Year != 2001 || movie.Length != 122 {
+		t.Errorf("year %d, length %d, want 2001, 122", movie.Year, movie.Length)
+	}
+	if movie.TmdbType != "movie" || movie.TmdbID != 194 || movie.ImdbID != "tt0211915" {
+		t.Errorf("tmdb %s/%d, imdb %s, want movie/194, tt0211915", movie.TmdbType, movie.TmdbID, movie.ImdbID)
+	}
+	if movie.Synopsis != "Amélie changes lives." {
+		t.Errorf("synopsis = %q", movie.Synopsis)
+	}
+
+	slugs := func(terms []database.Term) []string {
+		var out []string
+		for _, term := range terms {
+			out = append(out, term.Slug)
+		}
+		return out
+	}
+	if got, want := slugs(movie.Genres), []string{"comedy", "romance"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("genres = %v, want %v", got, want)
+	}
+	if got, want := slugs(movie.Themes), []string{"quirky-romance", "paris"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("themes = %v, want %v", got, want)
+	}
+	if got, want := slugs(movie.Countries), []string{"france"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("countries = %v, want %v", got, want)
+	}
+	if got, want := slugs(movie.Languages), []string{"french", "english"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("languages = %v, want %v", got, want)
+	}
+	if movie.PrimaryLanguage == nil || movie.PrimaryLanguage.Slug != "french" {
+		t.Errorf("primary language = %v, want french", movie.PrimaryLanguage)
+	}
+}
+
+func TestScrapeMovieDetailsCredits(t *testing.T) {
+	s := newFixtureScraper(t)
+
+	movie := database.Movie{LetterboxdID: "/film/paterson", LetterboxdURL: "/film/paterson/"}
+	if err := s.scrapeMovieDetails(context.Background(), &movie); err != nil {
+		t.Fatalf("scrapeMovieDetails: %v", err)
+	}
+
+	if movie.Year != 2016 || movie.Length != 118 || movie.LetterboxdRating != 3.98 {
+		t.Errorf("year %d, length %d, rating %v, want 2016, 118, 3.98", movie.Year, movie.Length, movie.LetterboxdRating)
+	}
+	if movie.Director != "Jim Jarmusch" || movie.Cast != "Adam Driver" || movie.Writers != "Jim Jarmusch" {
+		t.Errorf("director %q, cast %q, writers %q", movie.Director, movie.Cast, movie.Writers)
+	}
+
+	var roles []string
+	for _, credit := range movie.Credits {
+		roles = append(roles, credit.Role+":"+credit.PersonSlug)
+	}
+	want := []string{"director:jim-jarmusch", "actor:adam-driver", "writer:jim-jarmusch"}
+	if !reflect.DeepEqual(roles, want) {
+		t.Errorf("credits = %v, want %v", roles, want)
+	}
+}
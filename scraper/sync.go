@@ -0,0 +1,66 @@
+package scraper
+
+import (
+	"letterboxd-tracker/database"
+	"time"
+)
+
+// SyncOptions controls how much of a user's profile a sync walks
+type SyncOptions struct {
+	// Incremental lists films most recently watched first and stops paging
+	// once KnownStreak films in a row are already in the database
+	Incremental bool `json:"incremental"`
+	KnownStreak int  `json:"known_streak"`
+
+	// Refresh revisits films already in the database: the personal rating
+	// is re-read from the list pages for every film, and details scraped
+	// longer than MaxAge ago are scraped again
+	Refresh bool          `json:"refresh"`
+	MaxAge  time.Duration `json:"max_age"`
+}
+
+// DefaultKnownStreak is how many consecutive known films end an incremental sync
+const DefaultKnownStreak = 25
+
+// DefaultMaxAge is how old film details may get before a refresh re-scrapes them
+const DefaultMaxAge = 30 * 24 * time.Hour
+
+// Result summarises a finished sync. Changes lists every field
+// updated on films that were already in the database
+type Result struct {
+	Scraped   int                    `json:"scraped"`
+	Refreshed int                    `json:"refreshed"`
+	Skipped   int                    `json:"skipped"`
+	Failed    int                    `json:"failed"`
+	Viewings  int                    `json:"viewings"`
+	Liked     int                    `json:"liked"`
+	Watchlist int                    `json:"watchlist"`
+	Lists     int                    `json:"lists"`
+	Reviews   int                    `json:"reviews"`
+	Changes   []database.FieldChange `json:"changes"`
+}
+
+// takeUntilKnown filters a page down to items that are not stored yet.
+// streak carries the run of consecutive known items across pages; once it
+// reaches limit the rest of the page is dropped and stop is true
+func takeUntilKnown[T any](items []T, streak *int, limit int, known func(T) (bool, error)) (fresh []T, stop bool, err error) {
+	for _, item := range items {
+		exists, err := known(item)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if !exists {
+			*streak = 0
+			fresh = append(fresh, item)
+			continue
+		}
+
+		*streak++
+		if *streak >= limit {
+			return fresh, true, nil
+		}
+	}
+
+	return fresh, false, nil
+}
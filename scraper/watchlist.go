@@ -0,0 +1,174 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"letterboxd-tracker/database"
+	"log"
+	"sync"
+	"time"
+)
+
+// syncWatchlist scrapes the pages of the user's watchlist and saves them.
+// The watchlist is most recently added first, so in incremental mode paging
+// stops after a run of films already on the stored watchlist and the new
+// films are added at the top; otherwise the whole watchlist is read and
+// replaces the stored one. A watchlist that is private or missing counts
+// as empty. Films already stored with fresh details keep them, films
+// already watched take their details from the movies table, and the rest
+// have their detail page scraped.
+// Returns how many entries were saved and how many detail pages failed;
+// nothing is saved unless the pages were read without error
+func (s *Scraper) syncWatchlist(ctx context.Context, username string, opts SyncOptions) (saved, failed int, err error) {
+	var films []database.Movie
+	pageNum := 1
+	knownStreak := 0
+
+	for {
+		url := fmt.Sprintf("https://letterboxd.com/%s/watchlist/page/%d/", username, pageNum)
+		log.Printf("Scraping watchlist page %d: %s\n", pageNum, url)
+
+		pageFilms, hasNext, err := s.scrapeFilmsPage(ctx, url)
+		if err != nil {
+			if pageNum == 1 && errors.Is(err, ErrNotFound) {
+				log.Printf("Watchlist of %s is private or missing, treating it as empty\n", username)
+				break
+			}
+			return 0, 0, fmt.Errorf("failed to scrape watchlist page %d: %w", pageNum, err)
+		}
+
+		reachedKnown := false
+		if opts.Incremental {
+			pageFilms, reachedKnown, err = takeUntilKnown(pageFilms, &knownStreak, opts.KnownStreak,
+				func(film database.Movie) (bool, error) {
+					_, onList, err := s.db.GetWatchlistEntry(film.LetterboxdID)
+					return onList, err
+				})
+			if err != nil {
+				return 0, 0, err
+			}
+		}
+		films = append(films, pageFilms...)
+		s.report(Progress{Phase: PhaseWatchlist, Page: pageNum, Total: len(films)})
+
+		if reachedKnown || !hasNext {
+			break
+		}
+
+		pageNum++
+	}
+
+	entries := make([]database.WatchlistEntry, len(films))
+	for i, film := range films {
+		// The list page has no personal rating for unwatched films
+		film.Rating = 0
+		entries[i] = database.WatchlistEntry{Movie: film, Position: i + 1}
+	}
+
+	failed, err = s.enrichWatchlist(ctx, entries, opts)
+	if err != nil {
+		return 0, failed, err
+	}
+
+	if opts.Incremental {
+		err = s.db.AddToWatchlist(entries)
+	} else {
+		err = s.db.SaveWatchlist(entries)
+	}
+	if err != nil {
+		return 0, failed, err
+	}
+
+	return len(entries), failed, nil
+}
+
+// enrichWatchlist fills in the details of entries using the same number of
+// workers as the details pass. Returns how many entries were left without
+// details, or the context error if the sync was cancelled
+func (s *Scraper) enrichWatchlist(ctx context.Context, entries []database.WatchlistEntry, opts SyncOptions) (int, error) {
+	progress := Progress{Phase: PhaseWatchlist, Total: len(entries)}
+	failed := 0
+	var mu sync.Mutex
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < s.rateLimit.MaxInFlight; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				entry := &entries[i]
+				title := entry.Movie.Title
+
+				err := s.enrichWatchlistEntry(ctx, entry, opts)
+				if err != nil && ctx.Err() != nil {
+					continue
+				}
+
+				mu.Lock()
+				if err != nil {
+					log.Printf("Error scraping details for %s (%s): %v\n", title, errorKind(err), err)
+					failed++
+				}
+				progress.Current++
+				progress.Title = title
+				s.report(progress)
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for i := range entries {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return failed, err
+	}
+
+	return failed, nil
+}
+
+// enrichWatchlistEntry fills in the details of a watchlist film unless the
+// stored entry already has them. An entry left without details is saved
+// anyway and retried on the next sync
+func (s *Scraper) enrichWatchlistEntry(ctx context.Context, entry *database.WatchlistEntry, opts SyncOptions) error {
+	existing, onList, err := s.db.GetWatchlistEntry(entry.Movie.LetterboxdID)
+	if err != nil {
+		return err
+	}
+	if onList && !existing.Movie.DetailsScrapedAt.IsZero() &&
+		(!opts.Refresh || time.Since(existing.Movie.DetailsScrapedAt) < opts.MaxAge) {
+		// SaveWatchlist keeps the stored details
+		return nil
+	}
+
+	// A rewatch candidate may already be in the collection
+	watched, found, err := s.db.GetMovie(entry.Movie.LetterboxdID)
+	if err != nil {
+		return err
+	}
+	if found && !watched.DetailsScrapedAt.IsZero() &&
+		(!opts.Refresh || time.Since(watched.DetailsScrapedAt) < opts.MaxAge) {
+		watched.Rating = 0
+		watched.DateAdded = entry.Movie.DateAdded
+		entry.Movie = watched
+		return nil
+	}
+
+	log.Printf("Scraping details for watchlist film: %s\n", entry.Movie.Title)
+	if err := s.scrapeMovieDetails(ctx, &entry.Movie); err != nil {
+		return err
+	}
+	entry.Movie.DetailsScrapedAt = time.Now()
+
+	return nil
+}